@@ -0,0 +1,148 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+)
+
+// templateVariable matches the {name} placeholders of a branch_template.
+var templateVariable = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// newBranch is what `wt new` creates from the name it's given.
+type newBranch struct {
+	Branch   string
+	Worktree string
+	// Ticket and Template are recorded in the worktree's metadata
+	Ticket   string
+	Template string
+}
+
+// newBranchName turns the name given to `wt new` into the branch to create
+// and the worktree name. Without a branch_template, names typed at the
+// prompt are slugified and arguments are used as is. With one, the name
+// becomes the template's {slug} and the worktree is named after the slug;
+// names of existing branches are used unchanged so they can still be
+// checked out.
+func newBranchName(input string, prompted bool) (newBranch, error) {
+	template := config.GetBranchTemplate()
+	if template == "" || newNoTemplate {
+		if prompted {
+			input = slugify(input)
+		}
+		return newBranch{Branch: input, Worktree: input, Ticket: newTicket}, nil
+	}
+
+	if !prompted {
+		if exists, err := git.BranchExists(input); err == nil && exists {
+			return newBranch{Branch: input, Worktree: input, Ticket: newTicket}, nil
+		}
+	}
+
+	slug := slugify(input)
+	if slug == "" {
+		return newBranch{}, fmt.Errorf("'%s' has nothing to build a branch name from", input)
+	}
+	ticket := newTicket
+	if ticket == "" && templateUses(template, "ticket") {
+		if !prompted {
+			return newBranch{}, fmt.Errorf("branch_template %q needs a ticket; pass --ticket or --no-template", template)
+		}
+		var err error
+		if ticket, err = promptLine("Ticket: "); err != nil {
+			return newBranch{}, err
+		}
+	}
+	ticket = strings.TrimSpace(ticket)
+
+	branch, err := renderBranchTemplate(template, map[string]string{
+		"user":   branchUser(),
+		"ticket": ticket,
+		"slug":   slug,
+		"date":   time.Now().Format("2006-01-02"),
+	})
+	if err != nil {
+		return newBranch{}, err
+	}
+	return newBranch{Branch: branch, Worktree: slug, Ticket: ticket, Template: template}, nil
+}
+
+// renderBranchTemplate replaces the {name} placeholders of template with
+// vars, failing on unknown or empty ones.
+func renderBranchTemplate(template string, vars map[string]string) (string, error) {
+	var err error
+	branch := templateVariable.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := vars[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("unknown variable {%s} in branch_template; use {user}, {ticket}, {slug} or {date}", name)
+		} else if value == "" && err == nil {
+			err = fmt.Errorf("branch_template needs a value for {%s}", name)
+		}
+		return value
+	})
+	return branch, err
+}
+
+func templateUses(template, name string) bool {
+	return strings.Contains(template, "{"+name+"}")
+}
+
+// checkNewBranchName validates a branch wt is about to create against the
+// branch_pattern config value and git's ref-format rules.
+func checkNewBranchName(branch string) error {
+	if pattern := config.GetBranchPattern(); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid branch_pattern %q: %v", pattern, err)
+		}
+		if !re.MatchString(branch) {
+			return fmt.Errorf("branch name '%s' doesn't match branch_pattern %q", branch, pattern)
+		}
+	}
+	return git.CheckBranchName(branch)
+}
+
+// branchUser returns the {user} of branch templates: branch_user, else the
+// login name.
+func branchUser() string {
+	if name := config.GetBranchUser(); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return slugify(u.Username)
+	}
+	return slugify(os.Getenv("USER"))
+}
+
+// slugify lowercases s and turns each run of characters other than letters
+// and digits into a single hyphen, keeping "/" so "Feature/Fix Login!"
+// becomes "feature/fix-login".
+func slugify(s string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.ToLower(s), "/") {
+		var b strings.Builder
+		hyphen := false
+		for _, r := range segment {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				if hyphen && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				b.WriteRune(r)
+				hyphen = false
+			} else {
+				hyphen = true
+			}
+		}
+		if b.Len() > 0 {
+			segments = append(segments, b.String())
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,33 @@
+package worktree
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Fix login bug":          "fix-login-bug",
+		"  Add OAuth2 (Google)!": "add-oauth2-google",
+		"Feature/Fix  Login":     "feature/fix-login",
+		"???":                    "",
+	}
+	for input, want := range tests {
+		if got := slugify(input); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestRenderBranchTemplate(t *testing.T) {
+	vars := map[string]string{"user": "ada", "ticket": "ABC-12", "slug": "fix-login", "date": "2024-05-01", "empty": ""}
+
+	got, err := renderBranchTemplate("{user}/{ticket}-{slug}/{date}", vars)
+	if err != nil || got != "ada/ABC-12-fix-login/2024-05-01" {
+		t.Errorf("renderBranchTemplate() = %q, %v", got, err)
+	}
+
+	if _, err := renderBranchTemplate("{user}/{team}-{slug}", vars); err == nil {
+		t.Error("expected an error for an unknown variable")
+	}
+	if _, err := renderBranchTemplate("{empty}-{slug}", vars); err == nil {
+		t.Error("expected an error for an empty variable")
+	}
+}
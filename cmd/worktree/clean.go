@@ -0,0 +1,215 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+)
+
+var (
+	cleanMerged bool
+	cleanDryRun bool
+	cleanForce  bool
+)
+
+type cleanCandidate struct {
+	Worktree git.Worktree
+	Reason   string
+}
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean --merged",
+	Short: "Delete worktrees whose branches are merged",
+	Long: `Finds worktrees whose branches are done and deletes the worktree, the local
+branch and the tmux session in one batch. A branch is considered done when it
+is merged into the default branch, when its changes landed there as a squash
+merge or cherry-pick, or when its upstream branch was deleted ([gone]) and it
+has no commits that exist nowhere else.
+Worktrees with uncommitted changes, locked worktrees and worktrees matching a
+protected pattern are never removed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !cleanMerged {
+			fmt.Fprintf(os.Stderr, "Error: specify what to clean (currently only --merged is supported)\n")
+			os.Exit(1)
+		}
+
+		if !git.IsGitRepository() {
+			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+			os.Exit(1)
+		}
+
+		repoName, err := repositoryName()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Refuse to clean anything when protection can't be checked
+		if _, err := config.GetProtected(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: can't check protected patterns: %v\n", err)
+			os.Exit(1)
+		}
+
+		worktrees, err := git.ListWorktrees(repoName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(worktrees) == 0 {
+			fmt.Printf("No worktrees found for repository '%s'\n", repoName)
+			return
+		}
+
+		base, err := git.GetDefaultBranch(worktrees[0].Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		candidates, skipped := findMergedWorktrees(worktrees, base)
+
+		for _, s := range skipped {
+			fmt.Printf("⏭️  Skipping '%s': %s\n", s.Worktree.Name, s.Reason)
+		}
+
+		if len(candidates) == 0 {
+			fmt.Printf("✅ No merged worktrees found (base: %s)\n", base)
+			return
+		}
+
+		fmt.Printf("\n🧹 Merged worktrees (base: %s)\n", base)
+		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "WORKTREE\tBRANCH\tREASON\tPATH\n")
+		for _, c := range candidates {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Worktree.Name, c.Worktree.Branch, c.Reason, c.Worktree.Path)
+		}
+		w.Flush()
+
+		if cleanDryRun {
+			fmt.Println("\nDry run: nothing was removed")
+			return
+		}
+
+		if !cleanForce {
+			fmt.Printf("\n⚠️  WARNING: This will remove each worktree, its local branch and its tmux session.\n")
+			fmt.Printf("\nType 'yes' to confirm deletion: ")
+
+			var confirmation string
+			fmt.Scanln(&confirmation)
+
+			if strings.ToLower(confirmation) != "yes" {
+				fmt.Println("✅ Deletion cancelled")
+				return
+			}
+		}
+
+		failed := false
+		for _, c := range candidates {
+			if err := deleteWorktree(repoName, c.Worktree, false); err != nil {
+				fmt.Fprintf(os.Stderr, "Error deleting worktree '%s': %v\n", c.Worktree.Name, err)
+				failed = true
+				continue
+			}
+
+			// Squash-merged branches aren't ancestors of the base, so force
+			// deletion; gone branches were checked for unique commits but still
+			// get git's own safety check
+			force := c.Reason != git.MergedReasonUpstreamGone
+			if err := git.DeleteBranch(c.Worktree.Branch, force); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else {
+				fmt.Printf("🔄 Deleted branch '%s'\n", c.Worktree.Branch)
+			}
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Removed %d merged worktree%s\n", len(candidates), plural(len(candidates)))
+	},
+}
+
+// findMergedWorktrees splits worktrees into those whose branches are merged
+// into base and can be deleted, and merged ones that must be kept (with why).
+func findMergedWorktrees(worktrees []git.Worktree, base string) ([]cleanCandidate, []cleanCandidate) {
+	// The base may be a remote ref (origin/main); never clean the local copy of it
+	baseName := base
+	if i := strings.Index(base, "/"); i >= 0 {
+		baseName = base[i+1:]
+	}
+
+	var candidates, skipped []cleanCandidate
+	for _, wt := range worktrees {
+		if wt.Branch == "" || wt.Branch == "unknown" || strings.HasPrefix(wt.Branch, "detached") || wt.Branch == baseName {
+			continue
+		}
+
+		reason, err := git.BranchMergedReason(wt.Path, wt.Branch, base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		if reason == "" {
+			continue
+		}
+
+		if pattern, protected, err := config.MatchProtected(wt.Name, wt.Branch); err != nil {
+			skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: err.Error()})
+			continue
+		} else if protected {
+			skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: fmt.Sprintf("protected by pattern '%s'", pattern)})
+			continue
+		}
+
+		if wt.Locked {
+			skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: "locked" + lockReasonSuffix(wt)})
+			continue
+		}
+
+		if isCurrent, err := git.IsMainWorktree(wt.Path); err == nil && isCurrent {
+			skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: "it is the current worktree"})
+			continue
+		}
+
+		status, err := git.GetWorktreeStatus(wt.Path, "")
+		if err != nil {
+			skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: firstLine(err.Error())})
+			continue
+		}
+		if status.IsDirty() {
+			skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: "has uncommitted changes"})
+			continue
+		}
+
+		// A gone upstream says nothing about commits made since it was deleted
+		if reason == git.MergedReasonUpstreamGone {
+			unsaved, err := git.InspectUnsavedWork(wt.Path, wt.Branch)
+			if err != nil {
+				skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: firstLine(err.Error())})
+				continue
+			}
+			if n := len(unsaved.UnpushedCommits); n > 0 {
+				skipped = append(skipped, cleanCandidate{Worktree: wt, Reason: fmt.Sprintf("upstream is gone but it has %d commit%s on no other branch or remote", n, plural(n))})
+				continue
+			}
+		}
+
+		candidates = append(candidates, cleanCandidate{Worktree: wt, Reason: reason})
+	}
+
+	return candidates, skipped
+}
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanMerged, "merged", false, "remove worktrees whose branches are merged into the default branch")
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "only report what would be removed")
+	cleanCmd.Flags().BoolVar(&cleanForce, "force", false, "skip confirmation")
+}
@@ -6,20 +6,41 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/config"
 	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
 	"github.com/todoengineering/wt/internal/tmux"
+	"github.com/todoengineering/wt/internal/trash"
 	"github.com/todoengineering/wt/internal/ui"
 )
 
-var forceDelete bool
+var (
+	forceDelete            bool
+	discardChanges         bool
+	deleteBranchFlag       bool
+	deleteRemoteBranchFlag bool
+)
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete [worktree-name]",
+	Use:   "delete [worktree-name | branch]",
 	Short: "Delete a worktree (interactive)",
 	Long: `Interactive selection of worktree to delete using fzf.
 Shows worktree name and associated branch,
 requires explicit confirmation,
-prevents deletion of main repository worktree.`,
+prevents deletion of main repository worktree
+and of locked or protected worktrees.
+
+Worktrees with uncommitted changes, untracked files or commits that exist on
+no remote or other branch are never deleted unless --discard-changes is given.
+--force only skips the confirmation prompt.
+
+With --delete-branch the local branch is deleted as well (refusing unmerged
+branches unless --discard-changes is given), and with --delete-remote-branch
+the branch is also deleted from the remote it tracks. Defaults for both come
+from the delete_branch and delete_remote_branch config keys.
+
+The worktree can be named by its name or branch, or fuzzy-matched like with
+'wt open'; --force only deletes exact matches.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if !git.IsGitRepository() {
@@ -27,7 +48,7 @@ prevents deletion of main repository worktree.`,
 			os.Exit(1)
 		}
 
-		repoName, err := git.GetRepositoryName()
+		repoName, err := repositoryName()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -48,23 +69,21 @@ prevents deletion of main repository worktree.`,
 		var selectedWorktree git.Worktree
 
 		if len(args) > 0 {
-			// Worktree name provided as argument
-			worktreeName := args[0]
-			found := false
-			for _, wt := range worktrees {
-				if wt.Name == worktreeName {
-					selectedWorktree = wt
-					found = true
-					break
-				}
+			// Worktree name, branch or fuzzy match provided as argument
+			ref, guessed, err := resolveWorktree([]git.Project{{Name: repoName, Worktrees: worktrees}}, args[0], "Select a worktree to delete")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
-			if !found {
-				fmt.Fprintf(os.Stderr, "Error: worktree '%s' not found\n", worktreeName)
+			// --force skips the confirmation that would show what was matched
+			if guessed && forceDelete {
+				fmt.Fprintf(os.Stderr, "Error: '%s' only fuzzy-matches worktree '%s'; name it exactly to delete with --force\n", args[0], ref.Worktree.Name)
 				os.Exit(1)
 			}
+			selectedWorktree = ref.Worktree
 		} else {
 			// Interactive selection
-			selected, err := selectWorktreeForDeletion(worktrees)
+			selected, err := selectWorktreeForDeletion(repoName, worktrees)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error selecting worktree: %v\n", err)
 				os.Exit(1)
@@ -84,16 +103,100 @@ prevents deletion of main repository worktree.`,
 			os.Exit(1)
 		}
 
+		pattern, protected, err := config.MatchProtected(selectedWorktree.Name, selectedWorktree.Branch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if protected {
+			fmt.Fprintf(os.Stderr, "Error: worktree '%s' is protected by pattern '%s' and cannot be deleted\n", selectedWorktree.Name, pattern)
+			os.Exit(1)
+		}
+
+		if selectedWorktree.Locked {
+			fmt.Fprintf(os.Stderr, "Error: worktree '%s' is locked%s; run 'wt unlock %s' first\n",
+				selectedWorktree.Name, lockReasonSuffix(selectedWorktree), selectedWorktree.Name)
+			os.Exit(1)
+		}
+
+		// A prunable worktree's directory is already gone, so there's nothing to inspect
+		var unsaved git.UnsavedWork
+		if !selectedWorktree.Prunable {
+			unsaved, err = git.InspectUnsavedWork(selectedWorktree.Path, selectedWorktree.Branch)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error inspecting worktree: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if !unsaved.IsEmpty() {
+			printUnsavedWork(selectedWorktree, unsaved)
+			if !discardChanges {
+				fmt.Fprintf(os.Stderr, "\nError: worktree '%s' has unsaved work; commit or push it first, or re-run with --discard-changes to delete anyway\n", selectedWorktree.Name)
+				os.Exit(1)
+			}
+		} else if len(unsaved.Stashes) > 0 {
+			printUnsavedWork(selectedWorktree, unsaved)
+		}
+
+		deleteLocal := config.GetDeleteBranch()
+		if cmd.Flags().Changed("delete-branch") {
+			deleteLocal = deleteBranchFlag
+		}
+		deleteRemote := config.GetDeleteRemoteBranch()
+		if cmd.Flags().Changed("delete-remote-branch") {
+			deleteRemote = deleteRemoteBranchFlag
+		}
+
+		// Branch deletion only applies to worktrees that are on a branch
+		hasBranch := selectedWorktree.Branch != "" && selectedWorktree.Branch != "unknown" && !strings.HasPrefix(selectedWorktree.Branch, "detached")
+		deleteLocal = deleteLocal && hasBranch
+		deleteRemote = deleteRemote && hasBranch
+
+		// Look up the upstream before the local branch (and its config) is gone
+		var upstreamRemote, upstreamBranch string
+		if deleteRemote {
+			upstreamRemote, upstreamBranch, err = git.GetBranchUpstream(selectedWorktree.Branch)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			if upstreamRemote == "" {
+				fmt.Printf("Branch '%s' has no upstream, skipping remote branch deletion\n", selectedWorktree.Branch)
+				deleteRemote = false
+			}
+		}
+		if deleteRemote {
+			if reason := remoteDeletionRefusal(upstreamRemote, upstreamBranch, selectedWorktree.Branch); reason != "" {
+				fmt.Printf("Not deleting remote branch '%s/%s': %s\n", upstreamRemote, upstreamBranch, reason)
+				deleteRemote = false
+			}
+		}
+
 		if !forceDelete {
 			fmt.Printf("\n🗑️  Worktree Deletion Confirmation\n")
 			fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 			fmt.Printf("Worktree:    %s\n", selectedWorktree.Name)
 			fmt.Printf("Branch:      %s\n", selectedWorktree.Branch)
 			fmt.Printf("Path:        %s\n", selectedWorktree.Path)
-			fmt.Printf("\n⚠️  WARNING: This will permanently remove:\n")
+			if selectedWorktree.Prunable {
+				fmt.Printf("State:       prunable (%s)\n", selectedWorktree.PrunableReason)
+			}
+			if trash.Enabled() {
+				fmt.Printf("\n⚠️  WARNING: This will remove (recoverable with 'wt restore %s' for %d days):\n", selectedWorktree.Name, config.GetTrashRetentionDays())
+			} else {
+				fmt.Printf("\n⚠️  WARNING: This will permanently remove:\n")
+			}
 			fmt.Printf("   • The worktree directory and all its contents\n")
-			fmt.Printf("   • Any uncommitted changes in this worktree\n")
+			if !unsaved.IsEmpty() {
+				fmt.Printf("   • The unsaved work listed above\n")
+			}
 			fmt.Printf("   • Associated tmux session (if exists)\n")
+			if deleteLocal {
+				fmt.Printf("   • Local branch '%s'\n", selectedWorktree.Branch)
+			}
+			if deleteRemote {
+				fmt.Printf("   • Remote branch '%s/%s'\n", upstreamRemote, upstreamBranch)
+			}
 			fmt.Printf("\nType 'yes' to confirm deletion: ")
 
 			var confirmation string
@@ -105,55 +208,165 @@ prevents deletion of main repository worktree.`,
 			}
 		}
 
-		// Check for and kill associated tmux session
-		// Standard session name is <repo>-<worktree>. For backward compatibility,
-		// also try just <worktree> (older versions of wt new).
-		primarySession := tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", repoName, selectedWorktree.Name))
-		legacySession := tmux.SanitizeSessionName(selectedWorktree.Name)
+		if err := deleteWorktree(repoName, selectedWorktree, discardChanges); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting worktree: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Worktree '%s' has been deleted successfully\n", selectedWorktree.Name)
 
-		if tmux.IsInstalled() {
-			killed := false
-			if tmux.SessionExists(primarySession) {
-				fmt.Printf("🔄 Killing tmux session: %s\n", primarySession)
-				if err := tmux.KillSession(primarySession); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to kill tmux session: %v\n", err)
-				} else {
-					killed = true
+		if deleteLocal {
+			// Safe deletion refuses unmerged branches unless unsaved work may be discarded
+			if err := git.DeleteBranch(selectedWorktree.Branch, discardChanges); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				if !discardChanges {
+					fmt.Fprintf(os.Stderr, "Use --discard-changes to delete an unmerged branch\n")
 				}
-			}
-			// Try legacy session name if primary wasn't found/killed
-			if !killed && tmux.SessionExists(legacySession) {
-				fmt.Printf("🔄 Killing tmux session: %s\n", legacySession)
-				if err := tmux.KillSession(legacySession); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to kill tmux session: %v\n", err)
+				// The remote copy may be the only one left of work the local delete refused to drop
+				if deleteRemote {
+					fmt.Printf("Skipping deletion of remote branch '%s/%s' since the local branch was kept\n", upstreamRemote, upstreamBranch)
+					deleteRemote = false
 				}
+			} else {
+				fmt.Printf("✅ Deleted local branch '%s'\n", selectedWorktree.Branch)
 			}
 		}
 
-		// Delete the worktree
-		fmt.Printf("🔄 Deleting worktree '%s'...\n", selectedWorktree.Name)
-		if err := git.RemoveWorktree(selectedWorktree.Path); err != nil {
-			fmt.Fprintf(os.Stderr, "Error deleting worktree: %v\n", err)
-			os.Exit(1)
+		if deleteRemote {
+			fmt.Printf("🔄 Deleting remote branch '%s/%s'...\n", upstreamRemote, upstreamBranch)
+			if err := git.DeleteRemoteBranch(upstreamRemote, upstreamBranch); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else {
+				fmt.Printf("✅ Deleted remote branch '%s/%s'\n", upstreamRemote, upstreamBranch)
+			}
 		}
-
-		fmt.Printf("✅ Worktree '%s' has been deleted successfully\n", selectedWorktree.Name)
 	},
 }
 
+// remoteDeletionRefusal returns why the upstream branch of a deleted worktree
+// must stay on its remote, or "" if it may be deleted: the remote's default
+// branch and branches matching a protected pattern are never deleted.
+func remoteDeletionRefusal(remote, upstreamBranch, localBranch string) string {
+	if git.IsRemoteDefaultBranch(remote, upstreamBranch) {
+		return "it's the remote's default branch"
+	}
+	pattern, protected, err := config.MatchProtected(upstreamBranch, localBranch)
+	if err != nil {
+		return err.Error()
+	}
+	if protected {
+		return fmt.Sprintf("it's protected by pattern '%s'", pattern)
+	}
+	return ""
+}
+
 func init() {
 	deleteCmd.Flags().BoolVar(&forceDelete, "force", false, "skip confirmation and delete the worktree")
+	deleteCmd.Flags().BoolVar(&discardChanges, "discard-changes", false, "delete even if the worktree has uncommitted changes or unpushed commits")
+	deleteCmd.Flags().BoolVar(&deleteBranchFlag, "delete-branch", false, "also delete the local branch (default from delete_branch config)")
+	deleteCmd.Flags().BoolVar(&deleteRemoteBranchFlag, "delete-remote-branch", false, "also delete the branch on its upstream remote (default from delete_remote_branch config)")
+}
+
+// deleteWorktree kills the worktree's tmux session (if any) and removes the
+// worktree from disk and from git. Unless discard is set, git refuses to
+// remove worktrees with uncommitted changes. When the trash is enabled the
+// worktree's state is captured first so it can be restored with `wt restore`.
+func deleteWorktree(repoName string, worktree git.Worktree, discard bool) error {
+	var entry *trash.Entry
+	if trash.Enabled() && !worktree.Prunable {
+		if _, err := trash.Expire(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to expire old trash entries: %v\n", err)
+		}
+
+		var err error
+		entry, err = trash.Capture(repoName, worktree)
+		if err != nil {
+			return fmt.Errorf("failed to move worktree to trash: %w", err)
+		}
+		fmt.Printf("🗄️  Saved worktree state to trash (%s)\n", entry.ID)
+	}
+
+	killWorktreeSession(repoName, worktree)
+
+	fmt.Printf("🔄 Deleting worktree '%s'...\n", worktree.Name)
+	if err := git.RemoveWorktree(worktree.Path, discard); err != nil {
+		// The worktree is still there, so its trash entry would only get in
+		// the way of 'wt restore'
+		if entry != nil {
+			if err := trash.Remove(*entry); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		return err
+	}
+	git.RemoveEmptyParents(worktree.Path, git.GetWorktreeDir(repoName))
+
+	if err := metadata.Remove(repoName, worktree.Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return nil
+}
+
+func lockReasonSuffix(worktree git.Worktree) string {
+	if worktree.LockReason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", worktree.LockReason)
+}
+
+func printUnsavedWork(worktree git.Worktree, unsaved git.UnsavedWork) {
+	if len(unsaved.DirtyFiles) > 0 {
+		fmt.Printf("\n📝 Uncommitted changes in '%s':\n", worktree.Name)
+		for _, line := range unsaved.DirtyFiles {
+			fmt.Printf("   %s\n", line)
+		}
+	}
+	if len(unsaved.UnpushedCommits) > 0 {
+		fmt.Printf("\n📦 Commits not on any remote or other branch:\n")
+		for _, line := range unsaved.UnpushedCommits {
+			fmt.Printf("   %s\n", line)
+		}
+	}
+	if len(unsaved.Stashes) > 0 {
+		fmt.Printf("\n📚 Stashes created on '%s' (kept after deletion):\n", worktree.Branch)
+		for _, line := range unsaved.Stashes {
+			fmt.Printf("   %s\n", line)
+		}
+	}
+}
+
+func killWorktreeSession(repoName string, worktree git.Worktree) {
+	if !tmux.IsInstalled() {
+		return
+	}
+
+	// Standard session name is <repo>-<worktree>. For backward compatibility,
+	// also try just <worktree> (older versions of wt new).
+	primarySession := tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", repoName, worktree.Name))
+	legacySession := tmux.SanitizeSessionName(worktree.Name)
+
+	killed := false
+	if tmux.SessionExists(primarySession) {
+		fmt.Printf("🔄 Killing tmux session: %s\n", primarySession)
+		if err := tmux.KillSession(primarySession); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to kill tmux session: %v\n", err)
+		} else {
+			killed = true
+		}
+	}
+	// Try legacy session name if primary wasn't found/killed
+	if !killed && tmux.SessionExists(legacySession) {
+		fmt.Printf("🔄 Killing tmux session: %s\n", legacySession)
+		if err := tmux.KillSession(legacySession); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to kill tmux session: %v\n", err)
+		}
+	}
 }
 
-func selectWorktreeForDeletion(worktrees []git.Worktree) (git.Worktree, error) {
+func selectWorktreeForDeletion(repoName string, worktrees []git.Worktree) (git.Worktree, error) {
 	var items []ui.Item
-	for _, wt := range worktrees {
-		items = append(items, ui.Item{
-			TitleStr:       wt.Name,
-			DescriptionStr: fmt.Sprintf("[%s] %s", wt.Branch, wt.Path),
-			FilterStr:      wt.Name,
-			Value:          wt,
-		})
+	for _, wt := range sortWorktreesByUse(repoName, worktrees) {
+		items = append(items, worktreeItem(repoName, wt))
 	}
 
 	selected, err := ui.Select(items, "Select a worktree to delete")
@@ -0,0 +1,170 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+	"github.com/todoengineering/wt/internal/ui"
+)
+
+var (
+	describeDescription string
+	describeTicket      string
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe [worktree-name]",
+	Short: "Show or edit what a worktree is for",
+	Long: `Shows the record wt keeps for a worktree: description, ticket, base ref, and
+when and by whom it was created. --description and --ticket change them; pass
+an empty string to clear one. Records live under <worktrees_location>/.wt/,
+outside the worktree, so they never show up in git status.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoName, worktree := worktreeFromArgs(args, "Select a worktree to describe")
+
+		record, err := metadata.Load(repoName, worktree.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		editDescription, editTicket := cmd.Flags().Changed("description"), cmd.Flags().Changed("ticket")
+		if !editDescription && !editTicket {
+			printRecord(repoName, worktree, record)
+			return
+		}
+
+		// Worktrees created outside wt get a record on first edit
+		err = metadata.Update(repoName, worktree.Name, worktreeBranch(worktree), func(record *metadata.Worktree) {
+			if editDescription {
+				record.Description = strings.TrimSpace(describeDescription)
+			}
+			if editTicket {
+				record.Ticket = strings.TrimSpace(describeTicket)
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📝 Updated '%s/%s'\n", repoName, worktree.Name)
+	},
+}
+
+func printRecord(repoName string, worktree git.Worktree, record *metadata.Worktree) {
+	fmt.Printf("Worktree:    %s/%s\n", repoName, worktree.Name)
+	fmt.Printf("Path:        %s\n", worktree.Path)
+	fmt.Printf("Branch:      %s\n", worktree.Branch)
+	if record == nil {
+		fmt.Printf("\nNo description recorded; add one with 'wt describe %s --description <text>'\n", worktree.Name)
+		return
+	}
+
+	if record.Description != "" {
+		fmt.Printf("Description: %s\n", record.Description)
+	}
+	if record.Ticket != "" {
+		fmt.Printf("Ticket:      %s\n", record.Ticket)
+	}
+	if record.Base != "" && record.BaseCommit != "" {
+		fmt.Printf("Base:        %s (%s)\n", record.Base, git.ShortSHA(record.BaseCommit))
+	} else if record.Base != "" {
+		fmt.Printf("Base:        %s\n", record.Base)
+	}
+	if !record.CreatedAt.IsZero() {
+		created := record.CreatedAt.Local().Format("2006-01-02 15:04")
+		if record.CreatedBy != "" {
+			created += " by " + record.CreatedBy
+		}
+		fmt.Printf("Created:     %s\n", created)
+	}
+	if record.Template != "" {
+		fmt.Printf("Template:    %s\n", record.Template)
+	}
+	if !record.LastOpenedAt.IsZero() {
+		fmt.Printf("Opened:      %s (%d times)\n", record.LastOpenedAt.Local().Format("2006-01-02 15:04"), record.OpenCount)
+	}
+	if record.Pinned {
+		fmt.Printf("Pinned:      yes\n")
+	}
+}
+
+// recordLabel summarizes a worktree's ticket and description for list and
+// pickers, e.g. "ABC-12: Fix login bug", or "" when neither is recorded.
+func recordLabel(record *metadata.Worktree) string {
+	if record == nil {
+		return ""
+	}
+	switch {
+	case record.Ticket != "" && record.Description != "":
+		return record.Ticket + ": " + record.Description
+	case record.Ticket != "":
+		return record.Ticket
+	default:
+		return record.Description
+	}
+}
+
+// worktreeItem is a worktree in a picker. Its description shows the branch
+// and what the worktree is for, or its path when nothing is recorded, with
+// pinned worktrees marked. Filtering matches the branch, ticket and
+// description too.
+func worktreeItem(project string, wt git.Worktree) ui.Item {
+	record := loadRecord(project, wt.Name)
+	label := recordLabel(record)
+	description := label
+	if description == "" {
+		description = wt.Path
+	}
+	if record != nil && record.Pinned {
+		description = "📌 " + description
+	}
+	return ui.Item{
+		TitleStr:       wt.Name,
+		DescriptionStr: fmt.Sprintf("[%s] %s%s", wt.Branch, description, worktreeStateLabel(wt)),
+		FilterStr:      strings.Join(strings.Fields(wt.Name+" "+worktreeBranch(wt)+" "+label), " "),
+		Value:          wt,
+	}
+}
+
+// loadRecord returns a worktree's record, or nil if there's none or it
+// can't be read.
+func loadRecord(project, name string) *metadata.Worktree {
+	record, err := metadata.Load(project, name)
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+// worktreeBranch returns the branch a worktree has checked out, or "" when
+// it's detached.
+func worktreeBranch(wt git.Worktree) string {
+	if wt.Detached {
+		return ""
+	}
+	return wt.Branch
+}
+
+// creatorName is who a new worktree's record says created it: git's
+// user.name, else the login name.
+func creatorName() string {
+	if name := git.GetUserName(); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+func init() {
+	describeCmd.Flags().StringVar(&describeDescription, "description", "", "set what the worktree is for")
+	describeCmd.Flags().StringVar(&describeTicket, "ticket", "", "set the ticket the worktree is for")
+}
@@ -0,0 +1,101 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+)
+
+// fetch fetches remote (all remotes when empty), or only branch of it, unless
+// the last fetch is more recent than fetch_ttl. Fetches are abandoned after
+// fetch_timeout. Failures are only warnings: callers carry on with the local
+// refs they already have, so wt keeps working offline.
+func fetch(remote, branch string) {
+	if fetchedRecently() {
+		return
+	}
+	runFetch(remote, branch)
+}
+
+// fetchedRecently reports (and says) whether the last fetch is recent enough
+// per fetch_ttl to skip fetching.
+func fetchedRecently() bool {
+	ttl := config.GetFetchTTL()
+	if ttl <= 0 {
+		return false
+	}
+	last, ok := git.LastFetchTime()
+	if !ok || time.Since(last) >= ttl {
+		return false
+	}
+	fmt.Printf("Skipping fetch: last fetch was %s (fetch_ttl %s)\n", formatAge(last), ttl)
+	return true
+}
+
+func runFetch(remote, branch string) {
+	switch {
+	case remote == "":
+		fmt.Println("Fetching all remotes...")
+	case branch != "":
+		fmt.Printf("Fetching '%s/%s'...\n", remote, branch)
+	default:
+		fmt.Printf("Fetching '%s'...\n", remote)
+	}
+
+	opts := fetchOptions()
+	opts.Remote = remote
+	opts.Branch = branch
+	if err := git.Fetch(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using local refs\n", err)
+	}
+}
+
+// fetchOptions returns the options every fetch shares: fetch_timeout, and
+// git's own progress output when stderr is a terminal (kept out of logs and
+// pipes).
+func fetchOptions() git.FetchOptions {
+	opts := git.FetchOptions{Timeout: config.GetFetchTimeout()}
+	if info, err := os.Stderr.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		opts.Progress = os.Stderr
+	}
+	return opts
+}
+
+// fetchForBranch fetches only the remotes that own spec: the remote it names
+// (upstream/foo), the upstream of a local branch, or the remotes already known
+// to have it. Unknown branches may be new on a remote, so all remotes are
+// fetched for them.
+func fetchForBranch(spec string) {
+	if fetchedRecently() {
+		return
+	}
+
+	branch, remote, err := git.FindBranch(spec)
+	if err != nil || branch == nil {
+		if remote, name, ok := git.SplitRemoteRef(spec); ok {
+			runFetch(remote, name)
+			return
+		}
+		runFetch("", "")
+		return
+	}
+
+	switch {
+	case remote != "":
+		runFetch(remote, branch.Name)
+	case branch.IsLocal:
+		upstreamRemote, upstreamBranch, err := git.GetBranchUpstream(branch.Name)
+		if err != nil || upstreamRemote == "" || upstreamRemote == "." {
+			// Nothing to fetch for a purely local branch
+			return
+		}
+		runFetch(upstreamRemote, upstreamBranch)
+	default:
+		for _, r := range branch.Remotes {
+			runFetch(r.Remote, branch.Name)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+	"github.com/todoengineering/wt/internal/runner"
+	"github.com/todoengineering/wt/internal/tmux"
+)
+
+const baseCommit = "1111111111111111111111111111111111111111"
+
+// TestMain points wt at throwaway config and worktree directories before
+// the config is first loaded and cached.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "wt-test")
+	if err != nil {
+		panic(err)
+	}
+
+	configDir := filepath.Join(dir, "config")
+	if err := os.MkdirAll(filepath.Join(configDir, "wt"), 0755); err != nil {
+		panic(err)
+	}
+	// Disable the trash so deletion doesn't need a real worktree to capture
+	if err := os.WriteFile(filepath.Join(configDir, "wt", "config.toml"), []byte("trash_retention_days = 0\n"), 0644); err != nil {
+		panic(err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", configDir)
+	os.Setenv("WORKTREE_BASE_DIR", filepath.Join(dir, "worktrees"))
+	os.Setenv("EDITOR", "vim")
+	os.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// useFakes installs fake git and tmux runners for the duration of the test.
+func useFakes(t *testing.T) (gitFake, tmuxFake *runner.Fake) {
+	t.Helper()
+	gitFake, tmuxFake = runner.NewFake(), runner.NewFake()
+	prevGit, prevTmux := git.SetRunner(gitFake), tmux.SetRunner(tmuxFake)
+	t.Cleanup(func() {
+		git.SetRunner(prevGit)
+		tmux.SetRunner(prevTmux)
+	})
+	return gitFake, tmuxFake
+}
+
+// setFlags sets command flag variables for the duration of the test.
+func setFlags(t *testing.T, tmux, editor bool) {
+	t.Helper()
+	prevTmux, prevEditor := noTmux, noEditor
+	prevFrom, prevBase, prevNoFetch := newFromBranch, newBase, newNoFetch
+	noTmux, noEditor = !tmux, !editor
+	newNoFetch = true
+	t.Cleanup(func() {
+		noTmux, noEditor = prevTmux, prevEditor
+		newFromBranch, newBase, newNoFetch = prevFrom, prevBase, prevNoFetch
+	})
+}
+
+// scriptRepository answers the git commands every command starts with.
+func scriptRepository(fake *runner.Fake, refs string) {
+	fake.OnOutput("git rev-parse --git-dir", ".git\n")
+	fake.OnOutput("git rev-parse --git-common-dir", "/src/app/.git\n")
+	fake.OnOutput("git rev-parse --show-toplevel", "/src/app\n")
+	fake.OnOutput("git remote", "origin\nupstream\n")
+	fake.OnOutput("git for-each-ref --format=%(refname)%00%(objectname)", refs)
+	fake.OnOutput("git -C . worktree list --porcelain", "worktree /src/app\x00branch refs/heads/main\x00\x00")
+}
+
+func assertRan(t *testing.T, fake *runner.Fake, commands ...string) {
+	t.Helper()
+	for _, c := range commands {
+		if !fake.Ran(c) {
+			t.Errorf("expected %q to run; calls:\n  %s", c, strings.Join(fake.Calls(), "\n  "))
+		}
+	}
+}
+
+func TestNewCreatesBranchFromDefaultBranch(t *testing.T) {
+	gitFake, tmuxFake := useFakes(t)
+	setFlags(t, false, false)
+	scriptRepository(gitFake, "refs/heads/main\x00"+baseCommit+"\nrefs/remotes/origin/main\x00"+baseCommit+"\n")
+	gitFake.OnOutput("git -C . symbolic-ref --short refs/remotes/origin/HEAD", "origin/main\n")
+	gitFake.OnOutput("git rev-parse --verify --quiet origin/main^{commit}", baseCommit+"\n")
+	gitFake.OnOutput("git check-ref-format --branch feat", "feat\n")
+	gitFake.OnOutput("git branch --no-track", "")
+	gitFake.OnOutput("git worktree add", "")
+
+	newCmd.Run(newCmd, []string{"feat"})
+
+	worktreePath := filepath.Join(git.GetWorktreeDir("app"), "feat")
+	assertRan(t, gitFake,
+		"git branch --no-track feat "+baseCommit,
+		"git worktree add "+worktreePath+" feat",
+	)
+	if calls := tmuxFake.Calls(); len(calls) != 0 {
+		t.Errorf("tmux used despite --no-tmux: %q", calls)
+	}
+
+	record, err := metadata.Load("app", "feat")
+	if err != nil || record == nil {
+		t.Fatalf("metadata.Load() = %v, %v", record, err)
+	}
+	if record.Base != "origin/main" || record.BaseCommit != baseCommit || record.Branch != "feat" {
+		t.Errorf("record = %+v", record)
+	}
+}
+
+func TestNewFromRemoteBranchCreatesTrackingBranch(t *testing.T) {
+	gitFake, _ := useFakes(t)
+	setFlags(t, false, false)
+	newFromBranch = "upstream/fix"
+	scriptRepository(gitFake, "refs/heads/main\x00"+baseCommit+"\nrefs/remotes/origin/fix\x00aaa\nrefs/remotes/upstream/fix\x00bbb\n")
+	gitFake.OnOutput("git branch --track", "")
+	gitFake.OnOutput("git worktree list --porcelain", "worktree /src/app\x00branch refs/heads/main\x00\x00")
+	gitFake.OnOutput("git worktree add", "")
+
+	newCmd.Run(newCmd, nil)
+
+	assertRan(t, gitFake,
+		"git branch --track fix refs/remotes/upstream/fix",
+		"git worktree add "+filepath.Join(git.GetWorktreeDir("app"), "fix")+" fix",
+	)
+}
+
+func TestOpenSwitchesToExistingSession(t *testing.T) {
+	_, tmuxFake := useFakes(t)
+	setFlags(t, true, true)
+	tmuxFake.OnOutput("which tmux", "/usr/bin/tmux\n")
+	tmuxFake.OnOutput("tmux has-session", "")
+	tmuxFake.OnOutput("tmux send-keys", "")
+	tmuxFake.OnOutput("tmux switch-client", "")
+
+	openWorktree("app", git.Worktree{Name: "feat", Path: "/wt/app/feat", Branch: "feat"})
+
+	assertRan(t, tmuxFake,
+		"tmux send-keys -t app-feat:0.0 vim /wt/app/feat Enter",
+		"tmux switch-client -t app-feat",
+	)
+	if tmuxFake.Ran("tmux new-session") {
+		t.Errorf("created a new session: %q", tmuxFake.Calls())
+	}
+}
+
+func TestOpenCreatesSessionWithEditor(t *testing.T) {
+	_, tmuxFake := useFakes(t)
+	setFlags(t, true, true)
+	tmuxFake.OnOutput("which tmux", "/usr/bin/tmux\n")
+	tmuxFake.OnFail("tmux has-session", "can't find session")
+	tmuxFake.OnOutput("tmux new-session", "")
+	tmuxFake.OnOutput("tmux switch-client", "")
+
+	openWorktree("my.app", git.Worktree{Name: "feat", Path: "/wt/my.app/feat", Branch: "feat"})
+
+	assertRan(t, tmuxFake,
+		"tmux new-session -d -s my_app-feat -c /wt/my.app/feat vim /wt/my.app/feat",
+		"tmux switch-client -t my_app-feat",
+	)
+}
+
+func TestDeleteWorktreeKillsSessionAndForgetsMetadata(t *testing.T) {
+	gitFake, tmuxFake := useFakes(t)
+	gitFake.OnOutput("git worktree remove", "")
+	tmuxFake.OnOutput("which tmux", "/usr/bin/tmux\n")
+	tmuxFake.OnOutput("tmux has-session -t app-feat", "")
+	tmuxFake.OnFail("tmux has-session -t feat", "can't find session")
+	tmuxFake.OnOutput("tmux kill-session", "")
+
+	if err := metadata.Save(metadata.Worktree{Project: "app", Name: "feat", Branch: "feat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	wt := git.Worktree{Name: "feat", Path: "/wt/app/feat", Branch: "feat"}
+	if err := deleteWorktree("app", wt, false); err != nil {
+		t.Fatal(err)
+	}
+
+	assertRan(t, gitFake, "git worktree remove /wt/app/feat")
+	if gitFake.Ran("git worktree remove --force") {
+		t.Errorf("removed with --force without discarding: %q", gitFake.Calls())
+	}
+	assertRan(t, tmuxFake, "tmux kill-session -t app-feat")
+
+	if record, _ := metadata.Load("app", "feat"); record != nil {
+		t.Errorf("metadata still present: %+v", record)
+	}
+}
+
+func TestDeleteWorktreeDiscardForcesRemoval(t *testing.T) {
+	gitFake, tmuxFake := useFakes(t)
+	gitFake.OnOutput("git worktree remove", "")
+	tmuxFake.OnFail("which tmux", "")
+
+	wt := git.Worktree{Name: "feat", Path: "/wt/app/feat", Branch: "feat"}
+	if err := deleteWorktree("app", wt, true); err != nil {
+		t.Fatal(err)
+	}
+	assertRan(t, gitFake, "git worktree remove --force /wt/app/feat")
+}
+
+func TestDeleteWorktreeReportsGitFailure(t *testing.T) {
+	gitFake, tmuxFake := useFakes(t)
+	gitFake.OnFail("git worktree remove", "fatal: '/wt/app/feat' contains modified or untracked files")
+	tmuxFake.OnFail("which tmux", "")
+
+	wt := git.Worktree{Name: "feat", Path: "/wt/app/feat", Branch: "feat"}
+	if err := deleteWorktree("app", wt, false); err == nil {
+		t.Error("expected an error")
+	}
+}
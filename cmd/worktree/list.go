@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/todoengineering/wt/internal/git"
@@ -16,10 +18,86 @@ var (
 )
 
 type listEntry struct {
-	Project string `json:"project"`
-	Name    string `json:"name"`
-	Path    string `json:"path"`
-	Branch  string `json:"branch"`
+	Project        string `json:"project"`
+	Name           string `json:"name"`
+	Path           string `json:"path"`
+	Branch         string `json:"branch"`
+	Head           string `json:"head"`
+	Detached       bool   `json:"detached"`
+	Locked         bool   `json:"locked"`
+	LockReason     string `json:"lock_reason,omitempty"`
+	Prunable       bool   `json:"prunable"`
+	PrunableReason string `json:"prunable_reason,omitempty"`
+
+	// From wt's record of the worktree, when it has one
+	Description string     `json:"description,omitempty"`
+	Ticket      string     `json:"ticket,omitempty"`
+	Base        string     `json:"base,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	Template    string     `json:"template,omitempty"`
+}
+
+func newListEntry(project string, wt git.Worktree) listEntry {
+	entry := listEntry{
+		Project:        project,
+		Name:           wt.Name,
+		Path:           wt.Path,
+		Branch:         wt.Branch,
+		Head:           wt.Head,
+		Detached:       wt.Detached,
+		Locked:         wt.Locked,
+		LockReason:     wt.LockReason,
+		Prunable:       wt.Prunable,
+		PrunableReason: wt.PrunableReason,
+	}
+	if record := loadRecord(project, wt.Name); record != nil {
+		entry.Description = record.Description
+		entry.Ticket = record.Ticket
+		entry.Base = record.Base
+		if !record.CreatedAt.IsZero() {
+			entry.CreatedAt = &record.CreatedAt
+		}
+		entry.CreatedBy = record.CreatedBy
+		entry.Template = record.Template
+	}
+	return entry
+}
+
+// recordSuffix appends what a worktree is for to human-readable output,
+// e.g. " — ABC-12: Fix login bug".
+func recordSuffix(project string, wt git.Worktree) string {
+	if label := recordLabel(loadRecord(project, wt.Name)); label != "" {
+		return " — " + label
+	}
+	return ""
+}
+
+// detachedLabel marks worktrees without a branch, e.g. " (detached@a1b2c3d)".
+func detachedLabel(wt git.Worktree) string {
+	if !wt.Detached {
+		return ""
+	}
+	return " (" + wt.Branch + ")"
+}
+
+// worktreeStateLabel describes locked/prunable state for human-readable output.
+func worktreeStateLabel(wt git.Worktree) string {
+	var states []string
+	if wt.Locked {
+		if wt.LockReason != "" {
+			states = append(states, fmt.Sprintf("locked: %s", wt.LockReason))
+		} else {
+			states = append(states, "locked")
+		}
+	}
+	if wt.Prunable {
+		states = append(states, "prunable")
+	}
+	if len(states) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(states, ", ") + "]"
 }
 
 var listCmd = &cobra.Command{
@@ -38,7 +116,7 @@ var listCmd = &cobra.Command{
 				var out []listEntry
 				for _, p := range projects {
 					for _, wt := range p.Worktrees {
-						out = append(out, listEntry{Project: p.Name, Name: wt.Name, Path: wt.Path, Branch: wt.Branch})
+						out = append(out, newListEntry(p.Name, wt))
 					}
 				}
 				enc := json.NewEncoder(os.Stdout)
@@ -72,7 +150,7 @@ var listCmd = &cobra.Command{
 					continue
 				}
 				for _, wt := range p.Worktrees {
-					fmt.Printf("  %s -> %s\n", wt.Name, wt.Path)
+					fmt.Printf("  %s -> %s%s%s%s\n", wt.Name, wt.Path, detachedLabel(wt), worktreeStateLabel(wt), recordSuffix(p.Name, wt))
 				}
 			}
 			return
@@ -83,7 +161,7 @@ var listCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		repoName, err := git.GetRepositoryName()
+		repoName, err := repositoryName()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -98,7 +176,7 @@ var listCmd = &cobra.Command{
 		if listJSON {
 			var out []listEntry
 			for _, wt := range worktrees {
-				out = append(out, listEntry{Project: repoName, Name: wt.Name, Path: wt.Path, Branch: wt.Branch})
+				out = append(out, newListEntry(repoName, wt))
 			}
 			enc := json.NewEncoder(os.Stdout)
 			enc.SetIndent("", "  ")
@@ -124,7 +202,7 @@ var listCmd = &cobra.Command{
 
 		fmt.Printf("Worktrees for repository '%s':\n", repoName)
 		for _, wt := range worktrees {
-			fmt.Printf("  %s -> %s\n", wt.Name, wt.Path)
+			fmt.Printf("  %s -> %s%s%s%s\n", wt.Name, wt.Path, detachedLabel(wt), worktreeStateLabel(wt), recordSuffix(repoName, wt))
 		}
 	},
 }
@@ -0,0 +1,122 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/ui"
+)
+
+var lockReason string
+
+var lockCmd = &cobra.Command{
+	Use:   "lock [worktree-name]",
+	Short: "Lock a worktree",
+	Long: `Locks a worktree with 'git worktree lock' so it can't be pruned, moved or
+deleted, e.g. when it lives on a removable disk. Locked worktrees are shown in
+'wt list'.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoName, worktree := worktreeFromArgs(args, "Select a worktree to lock")
+
+		if worktree.Locked {
+			fmt.Printf("Worktree '%s' is already locked%s\n", worktree.Name, lockReasonSuffix(worktree))
+			return
+		}
+
+		if err := git.LockWorktree(worktree.Path, lockReason); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("🔒 Locked worktree '%s/%s'\n", repoName, worktree.Name)
+	},
+}
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock [worktree-name]",
+	Short: "Unlock a worktree",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoName, worktree := worktreeFromArgs(args, "Select a worktree to unlock")
+
+		if !worktree.Locked {
+			fmt.Printf("Worktree '%s' is not locked\n", worktree.Name)
+			return
+		}
+
+		if err := git.UnlockWorktree(worktree.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("🔓 Unlocked worktree '%s/%s'\n", repoName, worktree.Name)
+	},
+}
+
+// worktreeFromArgs returns the current repository's name and the worktree
+// named in args, or lets the user pick one interactively. A worktree only
+// fuzzy-matched by args is used after confirmation. It exits on error.
+func worktreeFromArgs(args []string, title string) (string, git.Worktree) {
+	if !git.IsGitRepository() {
+		fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+		os.Exit(1)
+	}
+
+	repoName, err := repositoryName()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	worktrees, err := git.ListWorktrees(repoName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(worktrees) == 0 {
+		fmt.Println("No worktrees found")
+		os.Exit(1)
+	}
+
+	if len(args) > 0 {
+		ref, guessed, err := resolveWorktree([]git.Project{{Name: repoName, Worktrees: worktrees}}, args[0], title)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if guessed {
+			fmt.Printf("'%s' matches worktree '%s'. Type 'yes' to use it: ", args[0], ref.Worktree.Name)
+
+			var confirmation string
+			fmt.Scanln(&confirmation)
+
+			if strings.ToLower(confirmation) != "yes" {
+				fmt.Fprintf(os.Stderr, "\nError: '%s' only fuzzy-matches worktree '%s'; name it exactly\n", args[0], ref.Worktree.Name)
+				os.Exit(1)
+			}
+		}
+		return repoName, ref.Worktree
+	}
+
+	var items []ui.Item
+	for _, wt := range sortWorktreesByUse(repoName, worktrees) {
+		items = append(items, worktreeItem(repoName, wt))
+	}
+
+	selected, err := ui.Select(items, title)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error selecting worktree: %v\n", err)
+		os.Exit(1)
+	}
+
+	return repoName, selected.Value.(git.Worktree)
+}
+
+func init() {
+	lockCmd.Flags().StringVar(&lockReason, "reason", "", "why the worktree is locked")
+}
@@ -0,0 +1,180 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+	"github.com/todoengineering/wt/internal/tmux"
+)
+
+var migrateDryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move a repository's worktrees into the repo_layout directory",
+	Long: `Moves the current repository's worktrees from where earlier repo_layout
+values filed them into the directory the current one names, e.g. from
+<worktrees_location>/api to <worktrees_location>/github.com/acme/api.
+Directories are moved as they are and 'git worktree repair' points git at
+their new location, so uncommitted work, locks and submodules are kept. wt's
+records and tmux sessions move along; trashed worktrees stay under the old
+name. Worktrees of other repositories in the same directory are left alone.
+Use --dry-run to only report what would be moved.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsGitRepository() {
+			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+			os.Exit(1)
+		}
+
+		repoName, err := git.GetRepositoryName()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !migrateDryRun {
+			if err := git.RegisterProject(repoName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		previous, err := previousProjectNames(repoName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		moves, err := git.PlanProjectMigration(repoName, previous)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(moves) == 0 {
+			fmt.Printf("Nothing to migrate: worktrees are already under %s\n", git.GetWorktreeDir(repoName))
+			return
+		}
+
+		failed := false
+		for _, move := range moves {
+			fmt.Printf("🚚 Moving %s -> %s\n", move.Worktree.Path, move.NewPath)
+			if migrateDryRun {
+				continue
+			}
+
+			if err := git.MoveWorktreeDir(move.Worktree.Path, move.NewPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed = true
+				continue
+			}
+			git.RemoveEmptyParents(move.Worktree.Path, git.GetWorktreeDir(move.From))
+
+			if err := metadata.MoveProject(move.From, repoName, move.Name); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed = true
+			}
+			if err := moveWorktreeSession(move.From, repoName, move.Name); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed = true
+			}
+		}
+
+		if migrateDryRun {
+			fmt.Println("\n(dry run: nothing was moved)")
+			return
+		}
+
+		forgetPreviousProjects(previous)
+
+		if failed {
+			fmt.Println("⚠️  Migration finished with warnings")
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Migrated %d worktree(s) to %s\n", len(moves), git.GetWorktreeDir(repoName))
+	},
+}
+
+// previousProjectNames returns the other names the current repository's
+// worktrees may be filed under: its name under every repo_layout, and any
+// project recorded for it.
+func previousProjectNames(repoName string) ([]string, error) {
+	mainPath, err := git.MainRepositoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := map[string]bool{repoName: true}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, layout := range []string{git.LayoutName, git.LayoutPath, git.LayoutRemote} {
+		if name, err := git.RepositoryIdentity(layout); err == nil {
+			add(name)
+		}
+	}
+	records, err := git.ListProjectRecords()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Repository == mainPath {
+			add(record.Name)
+		}
+	}
+	return names, nil
+}
+
+// forgetPreviousProjects drops the records of projects the current repository
+// no longer files worktrees under, and their directories once empty.
+func forgetPreviousProjects(names []string) {
+	mainPath, err := git.MainRepositoryPath()
+	if err != nil {
+		return
+	}
+	base := git.GetWorktreeBaseDir()
+	for _, name := range names {
+		dir := git.GetWorktreeDir(name)
+		// Remove fails on directories still holding other worktrees
+		if err := os.Remove(dir); err == nil || os.IsNotExist(err) {
+			git.RemoveEmptyParents(dir, base)
+		}
+		if record, err := git.LoadProjectRecord(name); err == nil && record != nil && record.Repository == mainPath {
+			if err := git.UnregisterProject(name); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+}
+
+// moveWorktreeSession renames the <repo>-<worktree> tmux session of a
+// worktree refiled from project oldProject under newProject.
+func moveWorktreeSession(oldProject, newProject, name string) error {
+	if !tmux.IsInstalled() {
+		return nil
+	}
+
+	session := tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", oldProject, name))
+	newSession := tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", newProject, name))
+	if session == newSession || !tmux.SessionExists(session) {
+		return nil
+	}
+	if tmux.SessionExists(newSession) {
+		return fmt.Errorf("tmux session '%s' already exists; leaving '%s' as is", newSession, session)
+	}
+	fmt.Printf("🔄 Renaming tmux session '%s' -> '%s'\n", session, newSession)
+	return tmux.RenameSession(session, newSession)
+}
+
+func init() {
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "only report what would be moved")
+}
@@ -6,24 +6,64 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/todoengineering/wt/internal/config"
 	"github.com/todoengineering/wt/internal/editor"
 	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
 	"github.com/todoengineering/wt/internal/tmux"
 	"github.com/todoengineering/wt/internal/ui"
 )
 
-var newFromBranch string
+var (
+	newFromBranch  string
+	newBase        string
+	newNoFetch     bool
+	newPR          int
+	newPRUpdate    bool
+	newPRRemote    string
+	newAt          string
+	newDetach      bool
+	newSparse      []string
+	newNoSparse    bool
+	newTicket      string
+	newNoTemplate  bool
+	newDescription string
+)
+
+// stdin is shared by prompts so input read ahead by one isn't lost to the next.
+var stdin = bufio.NewReader(os.Stdin)
+
+// promptLine prints prompt and returns the trimmed line typed in reply.
+func promptLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	input, err := stdin.ReadString('\n')
+	if err != nil && input == "" {
+		return "", err
+	}
+	return strings.TrimSpace(input), nil
+}
 
 var newCmd = &cobra.Command{
 	Use:   "new <name>",
 	Short: "Create new worktree",
-	Long: `Two modes:
-1) Default: Creates a new Git branch named <name> and a worktree for it.
+	Long: `Three modes:
+1) Default: Creates a new Git branch named <name> and a worktree for it. The
+   branch starts at --base, the base_branch config value, or the remote's
+   default branch (refs/remotes/origin/HEAD), which is fetched first. With a
+   branch_template such as "{user}/{ticket}-{slug}", <name> is slugified into
+   {slug} and the ticket comes from --ticket or a prompt.
 2) With --from <branch>: Creates a worktree for an existing branch, optionally named <name>.
-In both modes, opens the worktree in the configured editor and creates/switches to a tmux session.`,
+3) With --pr <number>: Fetches the pull request's head (refs/pull/<number>/head,
+   or the pr_refspec config value) into a local pr/<number> branch and creates
+   a worktree for it, optionally named <name>. --update fast-forwards an
+   existing pr/<number> branch to the latest head.
+With --at <ref> the new branch starts at a tag or commit instead; add --detach
+to check it out without creating a branch, e.g. to reproduce a bug on a
+released version.
+In all modes, opens the worktree in the configured editor and creates/switches to a tmux session.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		// Check if we're in a git repository
@@ -33,24 +73,131 @@ In both modes, opens the worktree in the configured editor and creates/switches
 		}
 
 		// Get repository name
-		repoName, err := git.GetRepositoryName()
+		repoName, err := repositoryName()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
+		if newFromBranch != "" && newBase != "" {
+			fmt.Fprintf(os.Stderr, "Error: --base can't be combined with --from\n")
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("pr") {
+			if newPR <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: invalid pull request number %d\n", newPR)
+				os.Exit(1)
+			}
+			if newFromBranch != "" || newBase != "" {
+				fmt.Fprintf(os.Stderr, "Error: --pr can't be combined with --from or --base\n")
+				os.Exit(1)
+			}
+		} else if newPRUpdate || newPRRemote != "" {
+			fmt.Fprintf(os.Stderr, "Error: --update and --remote require --pr\n")
+			os.Exit(1)
+		}
+		if newAt != "" {
+			if newFromBranch != "" || newBase != "" || newPR > 0 {
+				fmt.Fprintf(os.Stderr, "Error: --at can't be combined with --from, --base or --pr\n")
+				os.Exit(1)
+			}
+		} else if newDetach {
+			fmt.Fprintf(os.Stderr, "Error: --detach requires --at\n")
+			os.Exit(1)
+		}
+		if newNoTemplate && (newFromBranch != "" || newPR > 0 || newDetach) {
+			fmt.Fprintf(os.Stderr, "Error: --no-template only applies to new branches\n")
+			os.Exit(1)
+		}
+
+		sparsePaths, err := newSparsePaths()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		worktreeOptions := git.WorktreeOptions{SparsePaths: sparsePaths}
+
 		// Mode selection: from existing branch vs new branch
 		var worktreeName string
 		var worktreePath string
-		if newFromBranch != "" {
-			// Create worktree from an existing branch
-			// Always fetch remote branches for up-to-date list
-			fmt.Println("Fetching remote branches...")
-			if err := git.FetchRemoteBranches(); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to fetch remote branches: %v\n", err)
+		var record metadata.Worktree
+		if newPR > 0 {
+			// Check out the pull request as a local pr/<number> branch
+			branch, ref, err := preparePullRequestBranch(newPR)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if len(args) > 0 {
+				worktreeName = args[0]
+			} else {
+				worktreeName = branch
+			}
+
+			if gitWorktreeExists, gitWorktreePath := git.GitWorktreeExistsForBranch(branch); gitWorktreeExists {
+				switchToExistingWorktree(repoName, branch, gitWorktreePath)
+				return
+			}
+
+			if worktreeName, err = newWorktreeDir(repoName, worktreeName, branch); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Creating worktree '%s' for pull request #%d...\n", worktreeName, newPR)
+			p, err := git.CreateWorktreeWithOptions(repoName, worktreeName, branch, worktreeOptions)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			worktreePath = p
+			record.Branch = branch
+			// Compare against the pull request's head, so status shows
+			// local commits and sync, which fetches it again, picks up
+			// pushes
+			record.Base = ref
+			record.BaseCommit, _ = git.ResolveCommit(ref)
+		} else if newDetach {
+			// Check out a tag or commit without creating a branch
+			commit, err := resolveAt(newAt)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if len(args) > 0 {
+				worktreeName = args[0]
+			} else {
+				worktreeName = newAt
 			}
 
+			if worktreeName, err = newWorktreeDir(repoName, worktreeName, newAt); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Creating detached worktree '%s' at '%s' (%s)...\n", worktreeName, newAt, git.ShortSHA(commit))
+			p, err := git.CreateWorktreeWithOptions(repoName, worktreeName, commit, git.WorktreeOptions{Detach: true, SparsePaths: sparsePaths})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			worktreePath = p
+			record.Base = newAt
+			record.BaseCommit = commit
+		} else if newFromBranch != "" {
+			// Create worktree from an existing branch
 			sourceBranch := newFromBranch
+			if !newNoFetch {
+				if sourceBranch == ":pick" {
+					// The picker needs every remote's branches
+					fetch("", "")
+				} else {
+					fetchForBranch(sourceBranch)
+				}
+			}
+
 			if sourceBranch == ":pick" {
 				// Interactive selection
 				branches, err := git.ListAllBranches()
@@ -80,105 +227,169 @@ In both modes, opens the worktree in the configured editor and creates/switches
 				sourceBranch = picked
 			}
 
+			// Resolve remote branches (origin/foo, or foo when only remotes have it)
+			// to a local branch tracking the chosen remote
+			sourceBranch, err = prepareSourceBranch(sourceBranch)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
 			// Determine worktree name
 			if len(args) > 0 {
 				worktreeName = args[0]
 			} else {
-				// Default to sanitized branch name
-				worktreeName = git.SanitizeBranchName(sourceBranch)
+				// Default to the branch name, made a directory name by worktree_naming
+				worktreeName = sourceBranch
 			}
 
 			// If a worktree already exists for this branch, automatically switch to it
 			if gitWorktreeExists, gitWorktreePath := git.GitWorktreeExistsForBranch(sourceBranch); gitWorktreeExists {
-				fmt.Printf("A worktree already exists for branch '%s' at:\n  %s\n", sourceBranch, gitWorktreePath)
-				fmt.Printf("Switching to existing worktree...\n")
-				// Convert to Worktree struct for openWorktree function
-				existing := &git.Worktree{
-					Name:   sourceBranch, // Use branch name for better session naming
-					Path:   gitWorktreePath,
-					Branch: sourceBranch,
-				}
-				openWorktree(repoName, *existing)
+				switchToExistingWorktree(repoName, sourceBranch, gitWorktreePath)
 				return
 			}
 
+			if worktreeName, err = newWorktreeDir(repoName, worktreeName, sourceBranch); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
 			fmt.Printf("Creating worktree '%s' for branch '%s'...\n", worktreeName, sourceBranch)
-			p, err := git.CreateWorktree(repoName, worktreeName, sourceBranch)
+			p, err := git.CreateWorktreeWithOptions(repoName, worktreeName, sourceBranch, worktreeOptions)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 			worktreePath = p
+			record.Branch = sourceBranch
 		} else {
 			// Default behavior: create a new branch, then a worktree for it
 			// Get or prompt for worktree/branch name
+			var input string
 			if len(args) > 0 {
-				worktreeName = args[0]
+				input = args[0]
 			} else {
 				// Interactive mode - prompt for name
-				fmt.Print("Enter worktree name: ")
-				reader := bufio.NewReader(os.Stdin)
-				input, err := reader.ReadString('\n')
+				input, err = promptLine("Enter worktree name: ")
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
 					os.Exit(1)
 				}
-				worktreeName = strings.TrimSpace(input)
-				if worktreeName == "" {
+				if input == "" {
 					fmt.Fprintf(os.Stderr, "Error: worktree name cannot be empty\n")
 					os.Exit(1)
 				}
 			}
 
+			// Apply branch_template and slugify typed names
+			named, err := newBranchName(input, len(args) == 0)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			branchName := named.Branch
+			if branchName == "" {
+				fmt.Fprintf(os.Stderr, "Error: '%s' has nothing to build a branch name from\n", input)
+				os.Exit(1)
+			}
+			worktreeName = named.Worktree
+			record.Ticket = named.Ticket
+			record.Template = named.Template
+
 			// Check if branch already exists
-			branchExists, err := git.BranchExists(worktreeName)
+			branchExists, err := git.BranchExists(branchName)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error checking branch existence: %v\n", err)
 				os.Exit(1)
 			}
 
+			if branchExists && newAt != "" {
+				fmt.Fprintf(os.Stderr, "Error: branch '%s' already exists; pick another name or use --detach\n", branchName)
+				os.Exit(1)
+			}
+
 			if branchExists {
-				fmt.Printf("Branch '%s' already exists, using it...\n", worktreeName)
-				// Check if ANY worktree exists for this branch (not just ones managed by this tool)
-				if gitWorktreeExists, gitWorktreePath := git.GitWorktreeExistsForBranch(worktreeName); gitWorktreeExists {
-					fmt.Printf("A worktree already exists for branch '%s' at:\n  %s\n", worktreeName, gitWorktreePath)
-					fmt.Printf("Switching to existing worktree...\n")
-					// Convert to Worktree struct for openWorktree function
-					existing := &git.Worktree{
-						Name:   worktreeName, // Use branch name for better session naming
-						Path:   gitWorktreePath,
-						Branch: worktreeName,
+				fmt.Printf("Branch '%s' already exists, using it...\n", branchName)
+				if newBase != "" {
+					fmt.Fprintf(os.Stderr, "Warning: ignoring --base for existing branch '%s'\n", branchName)
+				}
+				if !git.LocalBranchExists(branchName) {
+					if _, err := prepareSourceBranch(branchName); err != nil {
+						fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+						os.Exit(1)
 					}
-					openWorktree(repoName, *existing)
+				}
+				// Check if ANY worktree exists for this branch (not just ones managed by this tool)
+				if gitWorktreeExists, gitWorktreePath := git.GitWorktreeExistsForBranch(branchName); gitWorktreeExists {
+					switchToExistingWorktree(repoName, branchName, gitWorktreePath)
 					return
 				}
-			} else {
-				// Create new branch
-				fmt.Printf("Creating branch '%s'...\n", worktreeName)
-				if err := git.CreateBranch(worktreeName); err != nil {
+			} else if err := checkNewBranchName(branchName); err != nil {
+				// Reject bad names before fetching or creating anything
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if worktreeName, err = newWorktreeDir(repoName, worktreeName, branchName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if !branchExists {
+				// Create new branch from the base rather than whatever is checked out here
+				base, baseCommit, err := resolveNewBranchBase()
+				if err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 					os.Exit(1)
 				}
+				fmt.Printf("Creating branch '%s' from '%s'...\n", branchName, base)
+				if err := git.CreateBranchAt(branchName, baseCommit); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				record.Base = base
+				record.BaseCommit = baseCommit
 			}
 
 			// Create worktree for the branch (existing or newly created)
-			fmt.Printf("Creating worktree for '%s'...\n", worktreeName)
-			p, err := git.CreateWorktree(repoName, worktreeName, worktreeName)
+			fmt.Printf("Creating worktree '%s' for '%s'...\n", worktreeName, branchName)
+			p, err := git.CreateWorktreeWithOptions(repoName, worktreeName, branchName, worktreeOptions)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 			worktreePath = p
+			record.Branch = branchName
 		}
 
 		fmt.Printf("Worktree created at: %s\n", worktreePath)
+		if len(sparsePaths) > 0 {
+			fmt.Printf("Sparse checkout of: %s\n", strings.Join(sparsePaths, ", "))
+		}
+
+		record.Project = repoName
+		record.Name = worktreeName
+		record.CreatedAt = time.Now()
+		record.CreatedBy = creatorName()
+		// Creating a worktree counts as opening it, so `wt open -` can return to it
+		record.LastOpenedAt = record.CreatedAt
+		record.OpenCount = 1
+		record.Description = newDescription
+		if record.Ticket == "" {
+			record.Ticket = newTicket
+		}
+		record.SparsePaths = sparsePaths
+		if err := metadata.Save(record); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 
 		// Create/switch tmux session and/or open editor according to flags
 		// Standardize on session name: <repo>-<worktree>
 		sessionName := tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", repoName, worktreeName))
+		openPath := sparseOpenPath(worktreePath, sparsePaths)
 		if noTmux {
 			if !noEditor {
-				if err := editor.OpenInEditor(worktreePath); err != nil {
+				if err := editor.OpenInEditor(openPath); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 				} else {
 					fmt.Printf("Opened in editor\n")
@@ -199,19 +410,19 @@ In both modes, opens the worktree in the configured editor and creates/switches
 					}
 				}
 
-				if err := tmux.CreateSessionWithNamedWindows(sessionName, worktreePath, windows); err != nil {
+				if err := tmux.CreateSessionWithNamedWindows(sessionName, openPath, windows); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 				} else {
 					fmt.Printf("Tmux session '%s' created with configured windows\n", sessionName)
 				}
 			} else if noEditor {
-				if err := tmux.CreateSession(sessionName, worktreePath); err != nil {
+				if err := tmux.CreateSession(sessionName, openPath); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 				} else {
 					fmt.Printf("Tmux session '%s' created\n", sessionName)
 				}
 			} else {
-				if err := tmux.CreateSessionWithCommand(sessionName, worktreePath, editor.GetEditorCommand(worktreePath)); err != nil {
+				if err := tmux.CreateSessionWithCommand(sessionName, openPath, editor.GetEditorCommand(openPath)); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 				} else {
 					fmt.Printf("Tmux session '%s' created with editor\n", sessionName)
@@ -219,7 +430,7 @@ In both modes, opens the worktree in the configured editor and creates/switches
 			}
 		} else {
 			if !noEditor {
-				if err := editor.OpenInEditor(worktreePath); err != nil {
+				if err := editor.OpenInEditor(openPath); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 				} else {
 					fmt.Printf("Opened in editor\n")
@@ -231,6 +442,113 @@ In both modes, opens the worktree in the configured editor and creates/switches
 
 func init() {
 	newCmd.Flags().StringVar(&newFromBranch, "from", "", "create a worktree from an existing branch (optionally provide <name> for worktree)")
+	newCmd.Flags().StringVar(&newBase, "base", "", "ref to create the new branch from (default: base_branch config or the remote's default branch)")
+	newCmd.Flags().BoolVar(&newNoFetch, "no-fetch", false, "use local refs only, without fetching the base, --from branch or pull request")
+	newCmd.Flags().IntVar(&newPR, "pr", 0, "create a worktree for pull request <number> on branch pr/<number>")
+	newCmd.Flags().BoolVar(&newPRUpdate, "update", false, "with --pr, fast-forward an existing pr/<number> branch to the pull request's latest head")
+	newCmd.Flags().StringVar(&newAt, "at", "", "tag or commit to create the new branch (or, with --detach, the worktree) at")
+	newCmd.Flags().BoolVar(&newDetach, "detach", false, "with --at, check out the ref in a detached worktree without creating a branch")
+	newCmd.Flags().StringSliceVar(&newSparse, "sparse", nil, "comma-separated directories to check out with a sparse checkout (default: sparse_paths config)")
+	newCmd.Flags().BoolVar(&newNoSparse, "no-sparse", false, "check out every file, ignoring sparse_paths")
+	newCmd.Flags().StringVar(&newTicket, "ticket", "", "ticket the worktree is for, also the {ticket} variable of branch_template")
+	newCmd.Flags().StringVar(&newDescription, "description", "", "what the worktree is for, shown by list, open and describe")
+	newCmd.Flags().BoolVar(&newNoTemplate, "no-template", false, "use <name> as the branch name, ignoring branch_template")
+	newCmd.Flags().StringVar(&newPRRemote, "remote", "", "with --pr, remote to fetch the pull request from (default: pr_remote config, upstream or origin)")
+}
+
+// switchToExistingWorktree opens the worktree that already has branch
+// checked out at path instead of creating another.
+func switchToExistingWorktree(repoName, branch, path string) {
+	fmt.Printf("A worktree already exists for branch '%s' at:\n  %s\n", branch, path)
+	fmt.Printf("Switching to existing worktree...\n")
+	if found, existing := git.WorktreeExistsForBranch(repoName, branch); found {
+		openWorktree(repoName, *existing)
+		return
+	}
+	// The branch is checked out in the main worktree; name the session after it
+	openWorktree(repoName, git.Worktree{Name: branch, Path: path, Branch: branch, IsMain: true})
+}
+
+// resolveNewBranchBase picks the ref a new branch starts from: --at or
+// --base, then the base_branch config value, then the remote's default
+// branch. Remote bases are fetched first unless disabled, so the branch
+// starts from the latest upstream commit. It returns the ref and the commit
+// it resolves to.
+func resolveNewBranchBase() (string, string, error) {
+	if newAt != "" {
+		commit, err := resolveAt(newAt)
+		return newAt, commit, err
+	}
+
+	base := newBase
+	if base == "" {
+		base = config.GetBaseBranch()
+	}
+	if base == "" {
+		defaultBranch, err := git.GetDefaultBranch(".")
+		if err != nil {
+			return "", "", fmt.Errorf("%v; pass --base or set base_branch", err)
+		}
+		base = defaultBranch
+	}
+
+	if !newNoFetch && config.GetFetchBase() {
+		if remote, branch, ok := git.SplitRemoteRef(base); ok {
+			fetch(remote, branch)
+		}
+	}
+
+	commit, err := git.ResolveCommit(base)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid base: %v", err)
+	}
+	return base, commit, nil
+}
+
+// resolveAt resolves the --at ref to a commit. Refs that aren't known
+// locally, such as a tag published since the last fetch, are looked for
+// again after fetching all remotes.
+func resolveAt(ref string) (string, error) {
+	commit, err := git.ResolveCommit(ref)
+	if err == nil || newNoFetch {
+		return commit, err
+	}
+	fetch("", "")
+	return git.ResolveCommit(ref)
+}
+
+// prepareSourceBranch returns the local branch to check out for spec. When
+// only remotes have the branch, a local branch tracking the chosen remote is
+// created; if several remotes have it and spec doesn't name one, the user
+// picks. Specs that aren't branches (tags, commits) are returned unchanged.
+func prepareSourceBranch(spec string) (string, error) {
+	branch, remote, err := git.FindBranch(spec)
+	if err != nil {
+		return "", err
+	}
+	if branch == nil || branch.IsLocal {
+		if branch != nil {
+			return branch.Name, nil
+		}
+		return spec, nil
+	}
+
+	if remote == "" {
+		if len(branch.Remotes) == 1 {
+			remote = branch.Remotes[0].Remote
+		} else {
+			remote, err = selectRemoteInteractive(*branch)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	fmt.Printf("Creating local branch '%s' tracking '%s/%s'...\n", branch.Name, remote, branch.Name)
+	if err := git.CreateTrackingBranch(branch.Name, remote); err != nil {
+		return "", err
+	}
+	return branch.Name, nil
 }
 
 // Branch selection helpers
@@ -245,18 +563,24 @@ func selectBranchInteractive(branches []git.Branch) (string, error) {
 
 	var items []ui.Item
 	for _, branch := range branches {
-		status := ""
-		if branch.IsLocal && branch.IsRemote {
-			status = "[local+remote]"
-		} else if branch.IsLocal {
-			status = "[local]"
-		} else {
-			status = "[remote]"
+		if !branch.IsLocal && len(branch.Remotes) > 1 {
+			// Remote-only branch on several remotes: list each copy so the
+			// choice of remote is explicit
+			for _, r := range branch.Remotes {
+				ref := r.Remote + "/" + branch.Name
+				items = append(items, ui.Item{
+					TitleStr:       branch.Name,
+					DescriptionStr: fmt.Sprintf("[remote] %s %s", ref, git.ShortSHA(r.SHA)),
+					FilterStr:      ref,
+					Value:          ref,
+				})
+			}
+			continue
 		}
 
 		items = append(items, ui.Item{
 			TitleStr:       branch.Name,
-			DescriptionStr: status,
+			DescriptionStr: branchDescription(branch),
 			FilterStr:      branch.Name,
 			Value:          branch.Name,
 		})
@@ -269,3 +593,51 @@ func selectBranchInteractive(branches []git.Branch) (string, error) {
 
 	return selected.Value.(string), nil
 }
+
+// branchDescription summarizes where a branch exists, e.g.
+// "[local+remote] a1b2c3d · origin a1b2c3d, upstream 9f8e7d6".
+func branchDescription(branch git.Branch) string {
+	status := ""
+	if branch.IsLocal && branch.IsRemote {
+		status = "[local+remote]"
+	} else if branch.IsLocal {
+		status = "[local]"
+	} else {
+		status = "[remote]"
+	}
+
+	var parts []string
+	if branch.IsLocal {
+		parts = append(parts, git.ShortSHA(branch.SHA))
+	}
+	var remotes []string
+	for _, r := range branch.Remotes {
+		remotes = append(remotes, fmt.Sprintf("%s %s", r.Remote, git.ShortSHA(r.SHA)))
+	}
+	if len(remotes) > 0 {
+		parts = append(parts, strings.Join(remotes, ", "))
+	}
+
+	return fmt.Sprintf("%s %s", status, strings.Join(parts, " · "))
+}
+
+// selectRemoteInteractive asks which remote's copy of branch to check out.
+func selectRemoteInteractive(branch git.Branch) (string, error) {
+	var items []ui.Item
+	for _, r := range branch.Remotes {
+		ref := r.Remote + "/" + branch.Name
+		items = append(items, ui.Item{
+			TitleStr:       ref,
+			DescriptionStr: git.ShortSHA(r.SHA),
+			FilterStr:      ref,
+			Value:          r.Remote,
+		})
+	}
+
+	selected, err := ui.Select(items, fmt.Sprintf("Branch '%s' exists on several remotes; select one to track", branch.Name))
+	if err != nil {
+		return "", err
+	}
+
+	return selected.Value.(string), nil
+}
@@ -1,6 +1,7 @@
 package worktree
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -17,43 +18,70 @@ var (
 )
 
 var openCmd = &cobra.Command{
-	Use:   "open",
+	Use:   "open [worktree | repo/worktree | -]",
 	Short: "Open existing worktree (interactive)",
 	Long: `Interactive two-step selection process using fzf:
 1. Select project from available repositories
 2. Select specific worktree within that project
-Opens selected worktree in configured editor and creates/switches to tmux session.`,
+Opens selected worktree in configured editor and creates/switches to tmux session.
+Pinned worktrees ('wt pin') are listed first, then the most frequently and
+recently opened ones. 'wt open -' goes straight back to the previously opened
+worktree, like 'cd -'.
+
+Given a worktree name, branch name or <repo>/<worktree>, opens that worktree
+directly. Anything else is fuzzy-matched: a single match is opened, several
+open the picker filtered on what was typed, and none suggests close names.
+Inside a repository names are looked up in that repository first, then in
+all of them.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		// Determine project scope
-		projects, err := git.ListAllProjects()
+		if len(args) == 1 && args[0] == "-" {
+			projectName, worktree, err := previousWorktree()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			openWorktree(projectName, worktree)
+			return
+		}
+
+		allProjects, err := git.ListAllProjects()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error listing projects: %v\n", err)
 			os.Exit(1)
 		}
 
-		// If filtering by project name
+		// Determine project scope
+		projects := allProjects
 		if openProjectFilter != "" {
-			var filtered []git.Project
-			for _, p := range projects {
-				if p.Name == openProjectFilter {
-					filtered = append(filtered, p)
-				}
-			}
-			projects = filtered
+			projects = filterProjects(allProjects, openProjectFilter)
 		} else if !openAllFlag && git.IsGitRepository() {
 			// If inside a repo and --all not set, limit to current repo
-			if repoName, err := git.GetRepositoryName(); err == nil {
-				var filtered []git.Project
-				for _, p := range projects {
-					if p.Name == repoName {
-						filtered = append(filtered, p)
-						break
-					}
-				}
-				projects = filtered
+			repoName, err := repositoryName()
+			var collision *git.ProjectCollisionError
+			if errors.As(err, &collision) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err == nil {
+				projects = filterProjects(allProjects, repoName)
 			}
 		}
 
+		if len(args) == 1 {
+			ref, _, err := resolveWorktree(projects, args[0], "Select a worktree to open")
+			var notFound *worktreeNotFoundError
+			if errors.As(err, &notFound) && openProjectFilter == "" && len(projects) < len(allProjects) {
+				ref, _, err = resolveWorktree(allProjects, args[0], "Select a worktree to open")
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			openWorktree(ref.Project, ref.Worktree)
+			return
+		}
+
 		if len(projects) == 0 {
 			fmt.Println("No projects with worktrees found")
 			fmt.Printf("Worktree base directory: %s\n", git.GetWorktreeBaseDir())
@@ -97,9 +125,19 @@ Opens selected worktree in configured editor and creates/switches to tmux sessio
 	},
 }
 
+func filterProjects(projects []git.Project, name string) []git.Project {
+	var filtered []git.Project
+	for _, p := range projects {
+		if p.Name == name {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
 func selectProjectInteractive(projects []git.Project) (git.Project, error) {
 	var items []ui.Item
-	for _, project := range projects {
+	for _, project := range sortProjectsByUse(projects) {
 		worktreeCount := len(project.Worktrees)
 		desc := fmt.Sprintf("%d worktree%s", worktreeCount,
 			func() string {
@@ -127,13 +165,8 @@ func selectProjectInteractive(projects []git.Project) (git.Project, error) {
 
 func selectWorktreeFromProject(project git.Project) (git.Worktree, error) {
 	var items []ui.Item
-	for _, worktree := range project.Worktrees {
-		items = append(items, ui.Item{
-			TitleStr:       worktree.Name,
-			DescriptionStr: worktree.Path,
-			FilterStr:      worktree.Name,
-			Value:          worktree,
-		})
+	for _, worktree := range sortWorktreesByUse(project.Name, project.Worktrees) {
+		items = append(items, worktreeItem(project.Name, worktree))
 	}
 
 	selected, err := ui.Select(items, fmt.Sprintf("Select a worktree from %s", project.Name))
@@ -146,14 +179,16 @@ func selectWorktreeFromProject(project git.Project) (git.Worktree, error) {
 
 func openWorktree(projectName string, worktree git.Worktree) {
 	fmt.Printf("Opening worktree: %s/%s\n", projectName, worktree.Name)
+	markOpened(projectName, worktree)
 
 	// Create or switch to tmux session
 	sessionName := fmt.Sprintf("%s-%s", projectName, worktree.Name)
 	sessionName = tmux.SanitizeSessionName(sessionName)
+	openPath := sparseOpenPath(worktree.Path, worktreeSparsePaths(projectName, worktree))
 
 	if noTmux {
 		if !noEditor {
-			if err := editor.OpenInEditor(worktree.Path); err != nil {
+			if err := editor.OpenInEditor(openPath); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to open editor: %v\n", err)
 			} else {
 				fmt.Printf("Opened in editor\n")
@@ -165,7 +200,7 @@ func openWorktree(projectName string, worktree git.Worktree) {
 		if tmux.SessionExists(sessionName) {
 			fmt.Printf("Switching to existing tmux session: %s\n", sessionName)
 			if !noEditor {
-				if err := tmux.SendCommandToSession(sessionName, editor.GetEditorCommand(worktree.Path)); err != nil {
+				if err := tmux.SendCommandToSession(sessionName, editor.GetEditorCommand(openPath)); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to open editor in session: %v\n", err)
 				}
 			}
@@ -175,18 +210,18 @@ func openWorktree(projectName string, worktree git.Worktree) {
 		} else {
 			fmt.Printf("Creating new tmux session: %s\n", sessionName)
 			if noEditor {
-				if err := tmux.CreateSession(sessionName, worktree.Path); err != nil {
+				if err := tmux.CreateSession(sessionName, openPath); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to create tmux session: %v\n", err)
 				}
 			} else {
-				if err := tmux.CreateSessionWithCommand(sessionName, worktree.Path, editor.GetEditorCommand(worktree.Path)); err != nil {
+				if err := tmux.CreateSessionWithCommand(sessionName, openPath, editor.GetEditorCommand(openPath)); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to create tmux session: %v\n", err)
 				}
 			}
 		}
 	} else {
 		if !noEditor {
-			if err := editor.OpenInEditor(worktree.Path); err != nil {
+			if err := editor.OpenInEditor(openPath); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to open editor: %v\n", err)
 			} else {
 				fmt.Printf("Opened in editor\n")
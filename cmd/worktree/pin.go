@@ -0,0 +1,43 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/metadata"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [worktree-name]",
+	Short: "Pin a worktree to the top of pickers",
+	Long: `Marks a worktree as a favorite: 'wt open' and other pickers list pinned
+worktrees first, ahead of the most frequently and recently opened ones.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoName, worktree := worktreeFromArgs(args, "Select a worktree to pin")
+		setPinned(repoName, worktree.Name, worktreeBranch(worktree), true)
+		fmt.Printf("📌 Pinned worktree '%s/%s'\n", repoName, worktree.Name)
+	},
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin [worktree-name]",
+	Short: "Unpin a worktree",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoName, worktree := worktreeFromArgs(args, "Select a worktree to unpin")
+		setPinned(repoName, worktree.Name, worktreeBranch(worktree), false)
+		fmt.Printf("Unpinned worktree '%s/%s'\n", repoName, worktree.Name)
+	},
+}
+
+func setPinned(repoName, name, branch string, pinned bool) {
+	err := metadata.Update(repoName, name, branch, func(record *metadata.Worktree) {
+		record.Pinned = pinned
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+)
+
+// resolvePullRequestRemote picks the remote pull requests are fetched from:
+// --remote, the pr_remote config value, then upstream (the main repository
+// in fork workflows), origin, or the only remote.
+func resolvePullRequestRemote() (string, error) {
+	if newPRRemote != "" {
+		return newPRRemote, nil
+	}
+	if remote := config.GetPRRemote(); remote != "" {
+		return remote, nil
+	}
+
+	remotes, err := git.ListRemotes()
+	if err != nil {
+		return "", err
+	}
+	for _, preferred := range []string{"upstream", "origin"} {
+		for _, r := range remotes {
+			if r == preferred {
+				return r, nil
+			}
+		}
+	}
+	switch len(remotes) {
+	case 0:
+		return "", fmt.Errorf("repository has no remotes to fetch pull requests from")
+	case 1:
+		return remotes[0], nil
+	default:
+		return "", fmt.Errorf("can't tell which remote has pull requests (%s); pass --remote or set pr_remote", strings.Join(remotes, ", "))
+	}
+}
+
+// preparePullRequestBranch makes sure the local pr/<number> branch exists,
+// fetching the pull request's head first. With --update an existing branch
+// is fast-forwarded to the latest head. It returns the branch and the ref
+// holding the head, e.g. refs/wt/pr/origin/123.
+func preparePullRequestBranch(number int) (string, string, error) {
+	remote, err := resolvePullRequestRemote()
+	if err != nil {
+		return "", "", err
+	}
+
+	branch := git.PullRequestBranch(number)
+	trackingRef := git.PullRequestTrackingRef(remote, number)
+	exists := git.LocalBranchExists(branch)
+
+	if !exists || newPRUpdate {
+		if !newNoFetch {
+			if err := fetchPullRequest(remote, number); err != nil {
+				return "", "", err
+			}
+		} else if _, err := git.ResolveCommit(trackingRef); err != nil {
+			return "", "", fmt.Errorf("pull request #%d hasn't been fetched from '%s' yet; run without --no-fetch", number, remote)
+		}
+	}
+
+	head, err := git.ResolveCommit(trackingRef)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !exists {
+		fmt.Printf("Creating branch '%s' from pull request #%d (%s)...\n", branch, number, git.ShortSHA(head))
+		if err := git.CreateBranchAt(branch, trackingRef); err != nil {
+			return "", "", err
+		}
+		return branch, trackingRef, nil
+	}
+
+	if !newPRUpdate {
+		fmt.Printf("Branch '%s' already exists, using it (pass --update to fetch new commits)...\n", branch)
+		return branch, trackingRef, nil
+	}
+
+	if current, err := git.ResolveCommit("refs/heads/" + branch); err == nil && current == head {
+		fmt.Printf("Branch '%s' is already up to date with pull request #%d\n", branch, number)
+		return branch, trackingRef, nil
+	}
+	if err := git.FastForwardBranch(branch, trackingRef); err != nil {
+		if errors.Is(err, git.ErrNotFastForward) {
+			return "", "", fmt.Errorf("%v; the pull request was force-pushed or '%s' has local commits", err, branch)
+		}
+		return "", "", err
+	}
+	fmt.Printf("Updated '%s' to %s\n", branch, git.ShortSHA(head))
+	return branch, trackingRef, nil
+}
+
+// fetchPullRequest fetches the head of pull request number from remote into
+// its tracking ref. Unlike other fetches, failing is an error: there's no
+// local copy to fall back to.
+func fetchPullRequest(remote string, number int) error {
+	ref := config.GetPRRefspec()
+	if ref == "" {
+		url, err := git.GetRemoteURL(remote)
+		if err != nil {
+			return err
+		}
+		ref = git.DefaultPullRequestRef(url)
+	}
+	refspec, err := git.PullRequestRefspec(ref, remote, number)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Fetching pull request #%d from '%s'...\n", number, remote)
+	opts := fetchOptions()
+	opts.Remote = remote
+	opts.Refspec = refspec
+	return git.Fetch(opts)
+}
@@ -0,0 +1,275 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/tmux"
+)
+
+// maxOrphanDepth is how many directories deep below an orphaned directory
+// names are checked against protected patterns.
+const maxOrphanDepth = 3
+
+var (
+	pruneDryRun bool
+	pruneForce  bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Clean up orphaned worktree directories, stale git metadata and tmux sessions",
+	Long: `Cross-checks the worktree directory against git's own worktree list and reports:
+  • directories under the worktree directory that git doesn't know about
+  • git worktree entries whose directories no longer exist (prunable)
+  • tmux sessions for this repository with no matching worktree
+Everything found is removed after confirmation. Use --dry-run to only report.
+Worktrees matching a protected pattern, and worktrees of other repositories
+sharing the worktree directory, are never touched.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsGitRepository() {
+			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+			os.Exit(1)
+		}
+
+		repoName, err := repositoryName()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Refuse to prune anything when protection can't be checked
+		if _, err := config.GetProtected(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: can't check protected patterns: %v\n", err)
+			os.Exit(1)
+		}
+
+		registered, err := git.ListGitWorktrees(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for i := range registered {
+			registered[i].Name = git.WorktreeName(repoName, registered[i].Path)
+		}
+
+		orphans, foreign, err := git.ListOrphanedWorktreeDirs(repoName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, dir := range foreign {
+			fmt.Printf("🛡️  Skipping worktree this repository doesn't own: %s\n", dir)
+		}
+
+		// Protected worktrees are never touched, e.g. one on an unmounted
+		// removable disk that git reports as prunable
+		var protected []string
+		var prunable []git.Worktree
+		for _, wt := range registered {
+			if !wt.Prunable {
+				continue
+			}
+			if reason, ok := protectedReason(wt.Name, wt.Branch); ok {
+				protected = append(protected, fmt.Sprintf("%s (%s)", wt.Path, reason))
+				continue
+			}
+			prunable = append(prunable, wt)
+		}
+
+		var unprotectedOrphans []string
+		for _, dir := range orphans {
+			if reason, ok := protectedReason(orphanNames(repoName, dir)...); ok {
+				protected = append(protected, fmt.Sprintf("%s (%s)", dir, reason))
+				continue
+			}
+			unprotectedOrphans = append(unprotectedOrphans, dir)
+		}
+		orphans = unprotectedOrphans
+
+		for _, p := range protected {
+			fmt.Printf("🛡️  Skipping protected: %s\n", p)
+		}
+
+		staleSessions := findStaleSessions(repoName, registered)
+
+		if len(orphans) == 0 && len(prunable) == 0 && len(staleSessions) == 0 {
+			fmt.Println("✅ Nothing to prune")
+			return
+		}
+
+		fmt.Printf("\n🧹 Prune Summary for '%s'\n", repoName)
+		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		if len(orphans) > 0 {
+			fmt.Printf("Orphaned directories (not registered with git):\n")
+			for _, dir := range orphans {
+				fmt.Printf("   • %s\n", dir)
+			}
+		}
+		if len(prunable) > 0 {
+			fmt.Printf("Stale git worktree entries:\n")
+			for _, wt := range prunable {
+				if wt.PrunableReason != "" {
+					fmt.Printf("   • %s (%s)\n", wt.Path, wt.PrunableReason)
+				} else {
+					fmt.Printf("   • %s\n", wt.Path)
+				}
+			}
+		}
+		if len(staleSessions) > 0 {
+			fmt.Printf("Tmux sessions without a worktree:\n")
+			for _, session := range staleSessions {
+				fmt.Printf("   • %s\n", session)
+			}
+		}
+
+		if pruneDryRun {
+			fmt.Println("\nDry run: nothing was removed")
+			return
+		}
+
+		if !pruneForce {
+			fmt.Printf("\n⚠️  WARNING: Orphaned directories will be permanently removed with all their contents.\n")
+			fmt.Printf("\nType 'yes' to confirm: ")
+
+			var confirmation string
+			fmt.Scanln(&confirmation)
+
+			if strings.ToLower(confirmation) != "yes" {
+				fmt.Println("✅ Prune cancelled")
+				return
+			}
+		}
+
+		failed := false
+		for _, dir := range orphans {
+			fmt.Printf("🔄 Removing directory: %s\n", dir)
+			if err := os.RemoveAll(dir); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to remove %s: %v\n", dir, err)
+				failed = true
+			}
+		}
+
+		// Remove entries one by one rather than with `git worktree prune` so
+		// protected entries are left alone
+		for _, wt := range prunable {
+			fmt.Printf("🔄 Pruning stale git worktree entry: %s\n", wt.Path)
+			if err := git.RemoveWorktree(wt.Path, false); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed = true
+			}
+		}
+
+		for _, session := range staleSessions {
+			fmt.Printf("🔄 Killing tmux session: %s\n", session)
+			if err := tmux.KillSession(session); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to kill tmux session: %v\n", err)
+				failed = true
+			}
+		}
+
+		if failed {
+			fmt.Println("⚠️  Prune finished with warnings")
+			os.Exit(1)
+		}
+		fmt.Println("✅ Prune complete")
+	},
+}
+
+// findStaleSessions returns tmux sessions named <repo>-<something> for which
+// no live worktree exists. Sessions are matched against both worktree names,
+// which keep their path within the worktree directory when nested, and branch
+// names, since existing worktrees opened via `wt new` are named after their
+// branch.
+func findStaleSessions(repoName string, registered []git.Worktree) []string {
+	sessions := tmux.ListSessions()
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	prefix := tmux.SanitizeSessionName(repoName + "-")
+
+	valid := make(map[string]bool)
+	for _, wt := range registered {
+		if wt.Prunable {
+			continue
+		}
+		valid[tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", repoName, wt.Name))] = true
+		if wt.Branch != "" {
+			valid[tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", repoName, wt.Branch))] = true
+		}
+	}
+
+	// Sessions for other projects whose names share our prefix (e.g. "api" and
+	// "api-gateway") must not be treated as ours.
+	var otherPrefixes []string
+	if projects, err := git.ListAllProjects(); err == nil {
+		for _, p := range projects {
+			other := tmux.SanitizeSessionName(p.Name + "-")
+			if p.Name != repoName && strings.HasPrefix(other, prefix) {
+				otherPrefixes = append(otherPrefixes, other)
+			}
+		}
+	}
+
+	var stale []string
+	for _, session := range sessions {
+		if !strings.HasPrefix(session, prefix) || valid[session] {
+			continue
+		}
+		foreign := false
+		for _, other := range otherPrefixes {
+			if strings.HasPrefix(session, other) {
+				foreign = true
+				break
+			}
+		}
+		if !foreign {
+			stale = append(stale, session)
+		}
+	}
+
+	return stale
+}
+
+// protectedReason reports whether names are protected and why. Names are
+// treated as protected when the patterns can't be checked.
+func protectedReason(names ...string) (string, bool) {
+	pattern, ok, err := config.MatchProtected(names...)
+	if err != nil {
+		return err.Error(), true
+	}
+	return fmt.Sprintf("pattern '%s'", pattern), ok
+}
+
+// orphanNames returns the worktree names of an orphaned directory and of the
+// directories below it, so a pattern such as release/* protects
+// release/1.0 even when all of release is orphaned.
+func orphanNames(repoName, dir string) []string {
+	var names []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		names = append(names, git.WorktreeName(repoName, path))
+		if rel, _ := filepath.Rel(dir, path); strings.Count(rel, string(filepath.Separator)) >= maxOrphanDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return names
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "only report what would be removed")
+	pruneCmd.Flags().BoolVar(&pruneForce, "force", false, "skip confirmation")
+}
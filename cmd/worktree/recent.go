@@ -0,0 +1,124 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+)
+
+// markOpened records that a worktree was just opened, for frecency
+// ordering and `wt open -`. The main worktree isn't one wt manages.
+func markOpened(project string, wt git.Worktree) {
+	if wt.IsMain {
+		return
+	}
+	if err := metadata.MarkOpened(project, wt.Name, worktreeBranch(wt), time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// projectRecords returns the records of project's worktrees by name.
+func projectRecords(project string) map[string]metadata.Worktree {
+	records, err := metadata.List(project)
+	if err != nil {
+		return nil
+	}
+	byName := make(map[string]metadata.Worktree, len(records))
+	for _, r := range records {
+		byName[r.Name] = r
+	}
+	return byName
+}
+
+// sortWorktreesByUse orders worktrees for pickers: pinned ones first, then
+// by frecency. Worktrees scoring the same keep their alphabetical order.
+func sortWorktreesByUse(project string, worktrees []git.Worktree) []git.Worktree {
+	records := projectRecords(project)
+	now := time.Now()
+	sorted := append([]git.Worktree(nil), worktrees...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := records[sorted[i].Name], records[sorted[j].Name]
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		return a.Frecency(now) > b.Frecency(now)
+	})
+	return sorted
+}
+
+// sortProjectsByUse orders projects for pickers by their most used
+// worktree, projects with pinned worktrees first.
+func sortProjectsByUse(projects []git.Project) []git.Project {
+	type usage struct {
+		pinned bool
+		score  float64
+	}
+	now := time.Now()
+	scores := make(map[string]usage, len(projects))
+	for _, p := range projects {
+		var u usage
+		for _, r := range projectRecords(p.Name) {
+			u.pinned = u.pinned || r.Pinned
+			if score := r.Frecency(now); score > u.score {
+				u.score = score
+			}
+		}
+		scores[p.Name] = u
+	}
+
+	sorted := append([]git.Project(nil), projects...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := scores[sorted[i].Name], scores[sorted[j].Name]
+		if a.pinned != b.pinned {
+			return a.pinned
+		}
+		return a.score > b.score
+	})
+	return sorted
+}
+
+// previousWorktree returns the most recently opened worktree other than the
+// one containing the current directory, the way `cd -` returns to the
+// previous directory.
+func previousWorktree() (string, git.Worktree, error) {
+	records, err := metadata.List("")
+	if err != nil {
+		return "", git.Worktree{}, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].LastOpenedAt.After(records[j].LastOpenedAt)
+	})
+
+	var currentProject, currentName string
+	if git.IsGitRepository() {
+		if repoName, err := git.GetRepositoryName(); err == nil {
+			if wt, ok := git.CurrentWorktree(repoName); ok {
+				currentProject, currentName = repoName, wt.Name
+			}
+		}
+	}
+
+	for _, r := range records {
+		if r.LastOpenedAt.IsZero() {
+			break
+		}
+		if r.Project == currentProject && r.Name == currentName {
+			continue
+		}
+		// Skip worktrees deleted behind wt's back
+		worktrees, err := git.ListWorktrees(r.Project)
+		if err != nil {
+			continue
+		}
+		for _, wt := range worktrees {
+			if wt.Name == r.Name {
+				return r.Project, wt, nil
+			}
+		}
+	}
+	return "", git.Worktree{}, fmt.Errorf("no previously opened worktree")
+}
@@ -0,0 +1,46 @@
+package worktree
+
+import (
+	"testing"
+	"time"
+
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+)
+
+func TestSortWorktreesByUse(t *testing.T) {
+	now := time.Now()
+	records := []metadata.Worktree{
+		{Project: "usage", Name: "daily", OpenCount: 10, LastOpenedAt: now.Add(-3 * time.Hour)},
+		{Project: "usage", Name: "just-now", OpenCount: 1, LastOpenedAt: now.Add(-5 * time.Minute)},
+		{Project: "usage", Name: "stale", OpenCount: 40, LastOpenedAt: now.Add(-30 * 24 * time.Hour)},
+		{Project: "usage", Name: "pinned", Pinned: true},
+	}
+	for _, r := range records {
+		if err := metadata.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var worktrees []git.Worktree
+	for _, name := range []string{"alpha", "daily", "just-now", "pinned", "stale"} {
+		worktrees = append(worktrees, git.Worktree{Name: name})
+	}
+
+	// daily: 10*2, stale: 40/4, just-now: 1*4; never opened keeps its place
+	want := []string{"pinned", "daily", "stale", "just-now", "alpha"}
+	got := sortWorktreesByUse("usage", worktrees)
+	for i, wt := range got {
+		if wt.Name != want[i] {
+			t.Fatalf("order = %v, want %v", names(got), want)
+		}
+	}
+}
+
+func names(worktrees []git.Worktree) []string {
+	var out []string
+	for _, wt := range worktrees {
+		out = append(out, wt.Name)
+	}
+	return out
+}
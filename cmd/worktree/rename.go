@@ -0,0 +1,166 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+	"github.com/todoengineering/wt/internal/tmux"
+)
+
+var renameBranch string
+
+var renameCmd = &cobra.Command{
+	Use:     "rename <old> <new>",
+	Aliases: []string{"move"},
+	Short:   "Rename a worktree together with its tmux session",
+	Long: `Moves the worktree directory with 'git worktree move' and renames its
+<repo>-<worktree> tmux session and wt's record of the worktree, keeping
+uncommitted work and open tmux windows. A nested worktree such as feature/x
+moves to the top of the worktree directory.
+With --branch, the worktree's branch is renamed as well.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		newName := args[1]
+		if err := validateWorktreeName(newName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		repoName, worktree := worktreeFromArgs(args[:1], "Select a worktree to rename")
+
+		if worktree.Name == newName {
+			fmt.Printf("Worktree is already named '%s'\n", newName)
+			return
+		}
+
+		if worktree.Locked {
+			fmt.Fprintf(os.Stderr, "Error: worktree '%s' is locked%s; run 'wt unlock %s' first\n",
+				worktree.Name, lockReasonSuffix(worktree), worktree.Name)
+			os.Exit(1)
+		}
+
+		if renameBranch != "" && worktree.Detached {
+			fmt.Fprintf(os.Stderr, "Error: worktree '%s' is not on a branch\n", worktree.Name)
+			os.Exit(1)
+		}
+
+		// Worktrees in the worktree directory move to its top level, nested
+		// or not; ones created elsewhere stay next to where they are
+		inWorktreeDir := git.InWorktreeDir(repoName, worktree.Path)
+		var newPath string
+		if inWorktreeDir {
+			if err := git.WorktreeDirConflict(repoName, newName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			newPath = filepath.Join(git.GetWorktreeDir(repoName), newName)
+		} else {
+			newPath = filepath.Join(filepath.Dir(worktree.Path), newName)
+			if _, err := os.Stat(newPath); err == nil {
+				fmt.Fprintf(os.Stderr, "Error: %s already exists\n", newPath)
+				os.Exit(1)
+			}
+		}
+
+		if renameBranch != "" && renameBranch != worktree.Branch {
+			if err := git.CheckBranchName(renameBranch); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if git.LocalBranchExists(renameBranch) {
+				fmt.Fprintf(os.Stderr, "Error: branch '%s' already exists\n", renameBranch)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("🔄 Moving %s -> %s\n", worktree.Path, newPath)
+		if err := git.MoveWorktree(worktree.Path, newPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if inWorktreeDir {
+			git.RemoveEmptyParents(worktree.Path, git.GetWorktreeDir(repoName))
+		}
+		// Records and sessions are keyed by the name git reports from now on
+		newName = git.WorktreeName(repoName, newPath)
+
+		// The worktree has moved; failures from here on are only warnings
+		failed := false
+
+		// The record only follows a branch that was actually renamed
+		branch := ""
+		if renameBranch != "" && renameBranch != worktree.Branch {
+			fmt.Printf("🔄 Renaming branch '%s' -> '%s'\n", worktree.Branch, renameBranch)
+			if err := git.RenameBranch(worktree.Branch, renameBranch); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed = true
+			} else {
+				branch = renameBranch
+			}
+		}
+
+		if err := metadata.Rename(repoName, worktree.Name, newName, branch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			failed = true
+		}
+
+		if err := renameWorktreeSession(repoName, worktree, newName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			failed = true
+		}
+
+		if failed {
+			fmt.Println("⚠️  Rename finished with warnings")
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Renamed worktree '%s' to '%s'\n", worktree.Name, newName)
+	},
+}
+
+// validateWorktreeName rejects names that would be changed when used as a
+// directory or tmux session name, so the worktree, its directory and its
+// session all keep the same name.
+func validateWorktreeName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid worktree name '%s'", name)
+	}
+	suggestion := tmux.SanitizeSessionName(git.SanitizeBranchName(name))
+	if suggestion != name {
+		return fmt.Errorf("invalid worktree name '%s': it may not contain characters such as '/', ':', '.' or spaces (try '%s')", name, suggestion)
+	}
+	return nil
+}
+
+// renameWorktreeSession renames the tmux session belonging to worktree so it
+// matches newName. Sessions using the legacy <worktree> name are moved to the
+// standard <repo>-<worktree> name.
+func renameWorktreeSession(repoName string, worktree git.Worktree, newName string) error {
+	if !tmux.IsInstalled() {
+		return nil
+	}
+
+	newSession := tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", repoName, newName))
+	for _, session := range []string{
+		tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", repoName, worktree.Name)),
+		tmux.SanitizeSessionName(worktree.Name),
+	} {
+		if !tmux.SessionExists(session) {
+			continue
+		}
+		if tmux.SessionExists(newSession) {
+			return fmt.Errorf("tmux session '%s' already exists; leaving '%s' as is", newSession, session)
+		}
+		fmt.Printf("🔄 Renaming tmux session '%s' -> '%s'\n", session, newSession)
+		return tmux.RenameSession(session, newSession)
+	}
+	return nil
+}
+
+func init() {
+	renameCmd.Flags().StringVar(&renameBranch, "branch", "", "also rename the worktree's branch")
+}
@@ -0,0 +1,210 @@
+package worktree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/ui"
+)
+
+// maxSuggestions caps the "did you mean" list of an unresolved identifier.
+const maxSuggestions = 3
+
+// worktreeRef is a worktree together with the project it belongs to.
+type worktreeRef struct {
+	Project  string
+	Worktree git.Worktree
+}
+
+// worktreeNotFoundError reports an identifier that matched no worktree, with
+// the closest names to suggest instead.
+type worktreeNotFoundError struct {
+	Identifier  string
+	Suggestions []string
+}
+
+func (e *worktreeNotFoundError) Error() string {
+	msg := fmt.Sprintf("worktree '%s' not found", e.Identifier)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = "'" + s + "'"
+	}
+	if len(quoted) == 1 {
+		return fmt.Sprintf("%s; did you mean %s?", msg, quoted[0])
+	}
+	return fmt.Sprintf("%s; did you mean %s or %s?", msg, strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
+
+// resolveWorktree finds the worktree identifier names among projects. An
+// exact match on a worktree's name or branch, either optionally prefixed with
+// "<project>/", wins. Otherwise the identifier is fuzzy-matched against the
+// same names: a single hit is returned directly and several open a picker
+// filtered on the identifier. guessed reports that the worktree was neither
+// named exactly nor picked by the user.
+func resolveWorktree(projects []git.Project, identifier, title string) (ref worktreeRef, guessed bool, err error) {
+	refs := worktreeRefs(projects)
+
+	var exact []worktreeRef
+	for _, r := range refs {
+		for _, name := range refNames(r) {
+			if identifier == name || identifier == r.Project+"/"+name {
+				exact = append(exact, r)
+				break
+			}
+		}
+	}
+	switch len(exact) {
+	case 1:
+		return exact[0], false, nil
+	case 0:
+	default:
+		ref, err := selectWorktreeRef(exact, len(projects) > 1, title, "")
+		return ref, false, err
+	}
+
+	matches := fuzzyMatches(refs, identifier)
+	switch len(matches) {
+	case 0:
+		return worktreeRef{}, false, &worktreeNotFoundError{
+			Identifier:  identifier,
+			Suggestions: suggestWorktrees(refs, identifier, len(projects) > 1),
+		}
+	case 1:
+		return matches[0], true, nil
+	default:
+		ref, err := selectWorktreeRef(refs, len(projects) > 1, title, identifier)
+		return ref, false, err
+	}
+}
+
+func worktreeRefs(projects []git.Project) []worktreeRef {
+	var refs []worktreeRef
+	for _, p := range projects {
+		for _, wt := range sortWorktreesByUse(p.Name, p.Worktrees) {
+			refs = append(refs, worktreeRef{Project: p.Name, Worktree: wt})
+		}
+	}
+	return refs
+}
+
+// refNames are the names a worktree can be addressed by: its own and, when
+// different, its branch's.
+func refNames(r worktreeRef) []string {
+	names := []string{r.Worktree.Name}
+	if branch := worktreeBranch(r.Worktree); branch != "" && branch != r.Worktree.Name {
+		names = append(names, branch)
+	}
+	return names
+}
+
+// fuzzyMatches returns the worktrees whose "<project>/<name>" or
+// "<project>/<branch>" fuzzy-matches identifier, best match first.
+func fuzzyMatches(refs []worktreeRef, identifier string) []worktreeRef {
+	var targets []string
+	var owners []int
+	for i, r := range refs {
+		for _, name := range refNames(r) {
+			targets = append(targets, r.Project+"/"+name)
+			owners = append(owners, i)
+		}
+	}
+
+	var matches []worktreeRef
+	seen := make(map[int]bool)
+	for _, m := range fuzzy.Find(identifier, targets) {
+		if owner := owners[m.Index]; !seen[owner] {
+			seen[owner] = true
+			matches = append(matches, refs[owner])
+		}
+	}
+	return matches
+}
+
+// suggestWorktrees returns the names closest to identifier by edit distance,
+// for when nothing matched it at all.
+func suggestWorktrees(refs []worktreeRef, identifier string, qualified bool) []string {
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	limit := max(2, len(identifier)/3)
+
+	var suggestions []suggestion
+	seen := make(map[string]bool)
+	for _, r := range refs {
+		best := -1
+		for _, name := range refNames(r) {
+			for _, candidate := range []string{name, r.Project + "/" + name} {
+				if d := editDistance(identifier, candidate); best < 0 || d < best {
+					best = d
+				}
+			}
+		}
+		name := r.Worktree.Name
+		if qualified {
+			name = r.Project + "/" + name
+		}
+		if best <= limit && !seen[name] {
+			seen[name] = true
+			suggestions = append(suggestions, suggestion{name, best})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+	var names []string
+	for i := 0; i < len(suggestions) && i < maxSuggestions; i++ {
+		names = append(names, suggestions[i].name)
+	}
+	return names
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
+
+// selectWorktreeRef picks one of refs, prefixing names with their project
+// when they span several, and starting filtered on filter.
+func selectWorktreeRef(refs []worktreeRef, qualified bool, title, filter string) (worktreeRef, error) {
+	var items []ui.Item
+	for _, r := range refs {
+		item := worktreeItem(r.Project, r.Worktree)
+		if qualified {
+			item.TitleStr = r.Project + "/" + item.TitleStr
+		}
+		item.FilterStr = r.Project + "/" + item.FilterStr
+		item.Value = r
+		items = append(items, item)
+	}
+
+	selected, err := ui.SelectFiltered(items, title, filter)
+	if err != nil {
+		return worktreeRef{}, err
+	}
+	return selected.Value.(worktreeRef), nil
+}
@@ -0,0 +1,87 @@
+package worktree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/todoengineering/wt/internal/git"
+)
+
+func TestResolveWorktree(t *testing.T) {
+	projects := []git.Project{
+		{Name: "api", Worktrees: []git.Worktree{
+			{Name: "feature_login", Branch: "feature/login"},
+			{Name: "billing", Branch: "billing"},
+		}},
+		{Name: "web", Worktrees: []git.Worktree{
+			{Name: "billing", Branch: "billing"},
+			{Name: "docs", Branch: "docs"},
+		}},
+	}
+
+	tests := []struct {
+		identifier string
+		project    string
+		name       string
+		guessed    bool
+	}{
+		{"feature_login", "api", "feature_login", false},
+		{"feature/login", "api", "feature_login", false},
+		{"web/billing", "web", "billing", false},
+		{"api/feature/login", "api", "feature_login", false},
+		{"flogin", "api", "feature_login", true},
+		{"dcs", "web", "docs", true},
+	}
+	for _, tt := range tests {
+		ref, guessed, err := resolveWorktree(projects, tt.identifier, "")
+		if err != nil {
+			t.Errorf("resolveWorktree(%q): %v", tt.identifier, err)
+			continue
+		}
+		if ref.Project != tt.project || ref.Worktree.Name != tt.name || guessed != tt.guessed {
+			t.Errorf("resolveWorktree(%q) = %s/%s (guessed %v), want %s/%s (guessed %v)",
+				tt.identifier, ref.Project, ref.Worktree.Name, guessed, tt.project, tt.name, tt.guessed)
+		}
+	}
+}
+
+func TestResolveWorktreeSuggests(t *testing.T) {
+	projects := []git.Project{{Name: "api", Worktrees: []git.Worktree{
+		{Name: "billing", Branch: "billing"},
+		{Name: "bolting", Branch: "bolting"},
+		{Name: "docs", Branch: "docs"},
+	}}}
+
+	_, _, err := resolveWorktree(projects, "bilingx", "")
+	var notFound *worktreeNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("err = %v, want a worktreeNotFoundError", err)
+	}
+	want := "worktree 'bilingx' not found; did you mean 'billing'?"
+	if err.Error() != want {
+		t.Errorf("err = %q, want %q", err, want)
+	}
+
+	_, _, err = resolveWorktree(projects, "zzz", "")
+	if err == nil || err.Error() != "worktree 'zzz' not found" {
+		t.Errorf("err = %v, want no suggestions", err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"feat", "feat", 0},
+		{"feat", "fet", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
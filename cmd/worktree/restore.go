@@ -0,0 +1,54 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/trash"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore a deleted worktree from the trash",
+	Long: `Recreates a deleted worktree from the trash: the branch (recreated at its saved
+tip if it was deleted), uncommitted changes, untracked files and copied files.
+<name> is the worktree name (the most recent deletion wins) or a trash ID from
+'wt trash list'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsGitRepository() {
+			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+			os.Exit(1)
+		}
+
+		repoName, err := repositoryName()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if _, err := trash.Expire(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to expire old trash entries: %v\n", err)
+		}
+
+		entry, err := trash.Find(repoName, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("🔄 Restoring worktree '%s' (deleted %s)...\n", entry.Name, formatAge(entry.DeletedAt))
+		worktreePath, err := trash.Restore(*entry)
+		if err != nil {
+			if worktreePath == "" {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		fmt.Printf("✅ Worktree '%s' restored at: %s\n", entry.Name, worktreePath)
+	},
+}
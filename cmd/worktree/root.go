@@ -34,4 +34,18 @@ func init() {
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(openCmd)
 	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(cleanCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(sparseCmd)
+	rootCmd.AddCommand(describeCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	rootCmd.AddCommand(migrateCmd)
 }
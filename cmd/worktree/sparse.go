@@ -0,0 +1,236 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+)
+
+var sparseWorktree string
+
+var sparseCmd = &cobra.Command{
+	Use:   "sparse",
+	Short: "Adjust the directories a sparse worktree checks out",
+	Long: `Lists, adds or removes the directories checked out in a worktree created with
+'wt new --sparse' (or the sparse_paths config key). Acts on the worktree
+containing the current directory unless --worktree is given.`,
+}
+
+var sparseListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the checked out directories",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, worktree, paths := sparseTarget()
+		fmt.Printf("Sparse paths of '%s':\n", worktree.Name)
+		for _, p := range paths {
+			fmt.Printf("  %s\n", p)
+		}
+	},
+}
+
+var sparseAddCmd = &cobra.Command{
+	Use:   "add <path>...",
+	Short: "Check out more directories",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoName, worktree, current := sparseTarget()
+		added, err := cleanSparsePaths(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := git.AddSparsePaths(worktree.Path, added); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		paths := current
+		for _, p := range added {
+			if !containsString(paths, p) {
+				paths = append(paths, p)
+			}
+		}
+		recordSparsePaths(repoName, worktree, paths)
+		fmt.Printf("Added %s to '%s'\n", strings.Join(added, ", "), worktree.Name)
+	},
+}
+
+var sparseRemoveCmd = &cobra.Command{
+	Use:   "remove <path>...",
+	Short: "Stop checking out directories",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoName, worktree, current := sparseTarget()
+		removed, err := cleanSparsePaths(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var paths []string
+		for _, p := range current {
+			if !containsString(removed, p) {
+				paths = append(paths, p)
+			}
+		}
+		for _, p := range removed {
+			if !containsString(current, p) {
+				fmt.Fprintf(os.Stderr, "Error: '%s' isn't checked out in '%s'\n", p, worktree.Name)
+				os.Exit(1)
+			}
+		}
+		if len(paths) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: can't remove every sparse path; run 'git sparse-checkout disable' in the worktree to check out everything\n")
+			os.Exit(1)
+		}
+
+		if err := git.SetSparsePaths(worktree.Path, paths); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		recordSparsePaths(repoName, worktree, paths)
+		fmt.Printf("Removed %s from '%s'\n", strings.Join(removed, ", "), worktree.Name)
+	},
+}
+
+// sparseTarget returns the worktree `wt sparse` acts on along with its
+// sparse paths, exiting if it isn't a sparse checkout.
+func sparseTarget() (string, git.Worktree, []string) {
+	var repoName string
+	var worktree git.Worktree
+	if sparseWorktree != "" {
+		repoName, worktree = worktreeFromArgs([]string{sparseWorktree}, "")
+	} else {
+		if !git.IsGitRepository() {
+			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+			os.Exit(1)
+		}
+		var err error
+		repoName, err = repositoryName()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		var ok bool
+		worktree, ok = git.CurrentWorktree(repoName)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: not inside a worktree; run from one or pass --worktree\n")
+			os.Exit(1)
+		}
+	}
+
+	if _, ok := git.ListSparsePaths(worktree.Path); !ok {
+		fmt.Fprintf(os.Stderr, "Error: worktree '%s' isn't a sparse checkout; create it with 'wt new --sparse'\n", worktree.Name)
+		os.Exit(1)
+	}
+	return repoName, worktree, worktreeSparsePaths(repoName, worktree)
+}
+
+// newSparsePaths returns the directories `wt new` checks out: --sparse, else
+// the sparse_paths config value. nil means a full checkout.
+func newSparsePaths() ([]string, error) {
+	if newNoSparse {
+		if len(newSparse) > 0 {
+			return nil, fmt.Errorf("--sparse can't be combined with --no-sparse")
+		}
+		return nil, nil
+	}
+	paths := newSparse
+	if len(paths) == 0 {
+		paths = config.GetSparsePaths()
+	}
+	return cleanSparsePaths(paths)
+}
+
+func cleanSparsePaths(paths []string) ([]string, error) {
+	var cleaned []string
+	for _, p := range paths {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		c, err := git.CleanSparsePath(p)
+		if err != nil {
+			return nil, err
+		}
+		if !containsString(cleaned, c) {
+			cleaned = append(cleaned, c)
+		}
+	}
+	return cleaned, nil
+}
+
+// worktreeSparsePaths returns the sparse paths of a worktree. git lists them
+// sorted, so the order they were given in is taken from wt's record, with
+// paths added outside wt appended. It returns nil for full checkouts.
+func worktreeSparsePaths(repoName string, worktree git.Worktree) []string {
+	paths, ok := git.ListSparsePaths(worktree.Path)
+	if !ok {
+		return nil
+	}
+	record, err := metadata.Load(repoName, worktree.Name)
+	if err != nil || record == nil {
+		return paths
+	}
+
+	var ordered []string
+	for _, p := range record.SparsePaths {
+		if containsString(paths, p) {
+			ordered = append(ordered, p)
+		}
+	}
+	for _, p := range paths {
+		if !containsString(ordered, p) {
+			ordered = append(ordered, p)
+		}
+	}
+	return ordered
+}
+
+// recordSparsePaths remembers the order of a worktree's sparse paths.
+func recordSparsePaths(repoName string, worktree git.Worktree, paths []string) {
+	record, err := metadata.Load(repoName, worktree.Name)
+	if err != nil || record == nil {
+		return
+	}
+	record.SparsePaths = paths
+	if err := metadata.Save(*record); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// sparseOpenPath returns where editors and tmux open a worktree: the first
+// sparse path when it exists, otherwise the worktree root.
+func sparseOpenPath(worktreePath string, sparsePaths []string) string {
+	if len(sparsePaths) == 0 {
+		return worktreePath
+	}
+	dir := filepath.Join(worktreePath, filepath.FromSlash(sparsePaths[0]))
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir
+	}
+	return worktreePath
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	sparseCmd.PersistentFlags().StringVarP(&sparseWorktree, "worktree", "w", "", "worktree to act on (default: the one containing the current directory)")
+	sparseCmd.AddCommand(sparseListCmd)
+	sparseCmd.AddCommand(sparseAddCmd)
+	sparseCmd.AddCommand(sparseRemoveCmd)
+}
@@ -0,0 +1,275 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+	"github.com/todoengineering/wt/internal/tmux"
+)
+
+var (
+	statusAll  bool
+	statusJSON bool
+)
+
+// Maximum number of worktrees inspected in parallel
+const statusConcurrency = 8
+
+type statusEntry struct {
+	Project        string    `json:"project"`
+	Name           string    `json:"name"`
+	Path           string    `json:"path"`
+	Branch         string    `json:"branch"`
+	Dirty          bool      `json:"dirty"`
+	Staged         int       `json:"staged"`
+	Unstaged       int       `json:"unstaged"`
+	Untracked      int       `json:"untracked"`
+	Conflicted     int       `json:"conflicted"`
+	Upstream       string    `json:"upstream,omitempty"`
+	Ahead          int       `json:"ahead"`
+	Behind         int       `json:"behind"`
+	Base           string    `json:"base,omitempty"`
+	BaseAhead      int       `json:"base_ahead"`
+	BaseBehind     int       `json:"base_behind"`
+	LastCommit     string    `json:"last_commit,omitempty"`
+	LastCommitTime time.Time `json:"last_commit_time,omitzero"`
+	TmuxSession    string    `json:"tmux_session,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show status of worktrees",
+	Long: `Shows a dashboard for every worktree of the current repository (or across all
+projects with --all): dirty state, staged/unstaged/untracked counts, ahead/behind
+against the upstream and the repository's base branch, the last commit and
+whether a tmux session exists.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var projects []git.Project
+		if statusAll {
+			p, err := git.ListAllProjects()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing projects: %v\n", err)
+				os.Exit(1)
+			}
+			projects = p
+		} else {
+			if !git.IsGitRepository() {
+				fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+				os.Exit(1)
+			}
+
+			repoName, err := repositoryName()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			worktrees, err := git.ListWorktrees(repoName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
+				os.Exit(1)
+			}
+			projects = []git.Project{{Name: repoName, Path: git.GetWorktreeDir(repoName), Worktrees: worktrees}}
+		}
+
+		entries := collectStatus(projects)
+
+		if statusJSON {
+			if entries == nil {
+				entries = []statusEntry{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(entries); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No worktrees found")
+			fmt.Printf("Worktree base directory: %s\n", git.GetWorktreeBaseDir())
+			return
+		}
+
+		printStatusTable(entries, statusAll)
+	},
+}
+
+// collectStatus gathers the status of every worktree concurrently, preserving
+// the project/worktree order of the input.
+func collectStatus(projects []git.Project) []statusEntry {
+	type job struct {
+		index   int
+		project string
+		base    string
+		wt      git.Worktree
+	}
+
+	var jobs []job
+	for _, p := range projects {
+		base := ""
+		if len(p.Worktrees) > 0 {
+			base, _ = git.GetDefaultBranch(p.Worktrees[0].Path)
+		}
+		for _, wt := range p.Worktrees {
+			wtBase := base
+			if recorded := metadata.GetBase(p.Name, wt.Name); recorded != "" {
+				wtBase = recorded
+			}
+			jobs = append(jobs, job{index: len(jobs), project: p.Name, base: wtBase, wt: wt})
+		}
+	}
+
+	entries := make([]statusEntry, len(jobs))
+	sem := make(chan struct{}, statusConcurrency)
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			entries[j.index] = worktreeStatusEntry(j.project, j.base, j.wt)
+		}(j)
+	}
+	wg.Wait()
+
+	return entries
+}
+
+func worktreeStatusEntry(project, base string, wt git.Worktree) statusEntry {
+	entry := statusEntry{
+		Project: project,
+		Name:    wt.Name,
+		Path:    wt.Path,
+		Branch:  wt.Branch,
+	}
+
+	status, err := git.GetWorktreeStatus(wt.Path, base)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Dirty = status.IsDirty()
+		entry.Staged = status.Staged
+		entry.Unstaged = status.Unstaged
+		entry.Untracked = status.Untracked
+		entry.Conflicted = status.Conflicted
+		entry.Upstream = status.Upstream
+		entry.Ahead = status.Ahead
+		entry.Behind = status.Behind
+		entry.Base = status.Base
+		entry.BaseAhead = status.BaseAhead
+		entry.BaseBehind = status.BaseBehind
+		entry.LastCommit = status.LastCommitSubject
+		entry.LastCommitTime = status.LastCommitTime
+	}
+
+	if tmux.IsInstalled() {
+		// Standard session name is <repo>-<worktree>, fall back to the legacy <worktree>
+		primarySession := tmux.SanitizeSessionName(fmt.Sprintf("%s-%s", project, wt.Name))
+		legacySession := tmux.SanitizeSessionName(wt.Name)
+		if tmux.SessionExists(primarySession) {
+			entry.TmuxSession = primarySession
+		} else if tmux.SessionExists(legacySession) {
+			entry.TmuxSession = legacySession
+		}
+	}
+
+	return entry
+}
+
+func printStatusTable(entries []statusEntry, showProject bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if showProject {
+		fmt.Fprintf(w, "PROJECT\t")
+	}
+	fmt.Fprintf(w, "WORKTREE\tBRANCH\tSTATE\tCHANGES\tUPSTREAM\tBASE\tLAST COMMIT\tTMUX\n")
+
+	for _, e := range entries {
+		if showProject {
+			fmt.Fprintf(w, "%s\t", e.Project)
+		}
+
+		if e.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\terror\t-\t-\t-\t%s\t-\n", e.Name, e.Branch, e.Error)
+			continue
+		}
+
+		state := "clean"
+		if e.Conflicted > 0 {
+			state = "conflict"
+		} else if e.Dirty {
+			state = "dirty"
+		}
+
+		changes := fmt.Sprintf("+%d ~%d ?%d", e.Staged, e.Unstaged, e.Untracked)
+
+		upstream := "-"
+		if e.Upstream != "" {
+			upstream = fmt.Sprintf("↑%d ↓%d", e.Ahead, e.Behind)
+		}
+
+		base := "-"
+		if e.Base != "" {
+			base = fmt.Sprintf("↑%d ↓%d %s", e.BaseAhead, e.BaseBehind, e.Base)
+		}
+
+		lastCommit := "-"
+		if e.LastCommit != "" {
+			lastCommit = fmt.Sprintf("%s (%s)", truncate(e.LastCommit, 40), formatAge(e.LastCommitTime))
+		}
+
+		session := "-"
+		if e.TmuxSession != "" {
+			session = e.TmuxSession
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Name, e.Branch, state, changes, upstream, base, lastCommit, session)
+	}
+	w.Flush()
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/(24*30)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/(24*365)))
+	}
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusAll, "all", false, "show status across all projects")
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "output JSON for scripting")
+}
@@ -0,0 +1,201 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/metadata"
+)
+
+var (
+	syncStrategy string
+	syncNoFetch  bool
+)
+
+const (
+	syncUpdated         = "updated"
+	syncUpToDate        = "up-to-date"
+	syncSkippedDirty    = "skipped-dirty"
+	syncSkippedDetached = "skipped-detached"
+	syncConflictAborted = "conflict-aborted"
+	syncFailed          = "failed"
+)
+
+type syncResult struct {
+	Name   string
+	Branch string
+	Target string
+	Result string
+	Detail string
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Bring all worktrees up to date",
+	Long: `Fetches all remotes once, then rebases (or merges) every worktree's branch onto
+its upstream, or onto its base branch when it has no upstream: the base recorded
+by 'wt new', falling back to the repository's default branch. Worktrees of
+pull requests fetch the pull request's latest head and sync with it.
+Worktrees with uncommitted changes or untracked files are skipped. Conflicting rebases and merges
+are aborted so no worktree is left in an intermediate state.
+
+The strategy defaults to the sync_strategy config value ("rebase" or "merge").`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsGitRepository() {
+			fmt.Fprintf(os.Stderr, "Error: not in a git repository\n")
+			os.Exit(1)
+		}
+
+		strategy := syncStrategy
+		if strategy == "" {
+			strategy = config.GetSyncStrategy()
+		}
+		if strategy != "rebase" && strategy != "merge" {
+			fmt.Fprintf(os.Stderr, "Error: unknown sync strategy '%s' (expected 'rebase' or 'merge')\n", strategy)
+			os.Exit(1)
+		}
+
+		repoName, err := repositoryName()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		worktrees, err := git.ListWorktrees(repoName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing worktrees: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(worktrees) == 0 {
+			fmt.Printf("No worktrees found for repository '%s'\n", repoName)
+			return
+		}
+
+		if !syncNoFetch {
+			fetch("", "")
+		}
+
+		base, _ := git.GetDefaultBranch(worktrees[0].Path)
+
+		var results []syncResult
+		for _, wt := range worktrees {
+			fmt.Printf("🔄 Syncing '%s'...\n", wt.Name)
+			// Branches created by wt new sync with the base they were created from
+			wtBase := base
+			if recorded := metadata.GetBase(repoName, wt.Name); recorded != "" {
+				wtBase = recorded
+			}
+			// Pull request heads aren't updated by fetching all remotes
+			if remote, number, ok := git.ParsePullRequestTrackingRef(wtBase); ok && !syncNoFetch {
+				if err := fetchPullRequest(remote, number); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+			results = append(results, syncWorktree(wt, wtBase, strategy))
+		}
+
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "WORKTREE\tBRANCH\tTARGET\tRESULT\tDETAIL\n")
+		for _, r := range results {
+			target := r.Target
+			if target == "" {
+				target = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Name, r.Branch, target, r.Result, r.Detail)
+		}
+		w.Flush()
+	},
+}
+
+func syncWorktree(wt git.Worktree, base, strategy string) syncResult {
+	result := syncResult{Name: wt.Name, Branch: wt.Branch}
+
+	if strings.HasPrefix(wt.Branch, "detached") {
+		result.Result = syncSkippedDetached
+		return result
+	}
+
+	status, err := git.GetWorktreeStatus(wt.Path, "")
+	if err != nil {
+		result.Result = syncFailed
+		result.Detail = firstLine(err.Error())
+		return result
+	}
+
+	// Untracked files count too: a rebase or merge bringing in a file of the
+	// same name would fail or overwrite them
+	if status.IsDirty() {
+		result.Result = syncSkippedDirty
+		result.Detail = fmt.Sprintf("%d staged, %d unstaged, %d untracked, %d conflicted", status.Staged, status.Unstaged, status.Untracked, status.Conflicted)
+		return result
+	}
+
+	result.Target = status.Upstream
+	if result.Target == "" {
+		result.Target = base
+	}
+	if result.Target == "" {
+		result.Result = syncFailed
+		result.Detail = "no upstream or base branch to sync with"
+		return result
+	}
+
+	_, behind, err := git.AheadBehind(wt.Path, "HEAD", result.Target)
+	if err != nil {
+		result.Result = syncFailed
+		result.Detail = firstLine(err.Error())
+		return result
+	}
+	if behind == 0 {
+		result.Result = syncUpToDate
+		return result
+	}
+
+	if strategy == "merge" {
+		err = git.MergeIntoWorktree(wt.Path, result.Target)
+	} else {
+		err = git.RebaseWorktree(wt.Path, result.Target)
+	}
+
+	switch {
+	case errors.Is(err, git.ErrConflictAborted):
+		result.Result = syncConflictAborted
+	case err != nil:
+		result.Result = syncFailed
+		result.Detail = firstLine(err.Error())
+	default:
+		result.Result = syncUpdated
+		result.Detail = fmt.Sprintf("%d new commit%s", behind, plural(behind))
+	}
+
+	return result
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", "", "how to integrate changes: rebase or merge (default from sync_strategy config)")
+	syncCmd.Flags().BoolVar(&syncNoFetch, "no-fetch", false, "sync against local refs without fetching first")
+}
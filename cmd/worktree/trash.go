@@ -0,0 +1,93 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/todoengineering/wt/internal/git"
+	"github.com/todoengineering/wt/internal/trash"
+)
+
+var (
+	trashListAll  bool
+	trashListJSON bool
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted worktrees",
+	Long: `Deleted worktrees are kept in a trash area under the worktree base directory
+for trash_retention_days days (default 7) so they can be recovered with
+'wt restore <name>'.`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deleted worktrees that can be restored",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := trash.Expire(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to expire old trash entries: %v\n", err)
+		}
+
+		project := ""
+		if !trashListAll {
+			if !git.IsGitRepository() {
+				fmt.Fprintf(os.Stderr, "Error: not in a git repository (use --all to list every project)\n")
+				os.Exit(1)
+			}
+			repoName, err := repositoryName()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			project = repoName
+		}
+
+		entries, err := trash.List(project)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing trash: %v\n", err)
+			os.Exit(1)
+		}
+
+		if trashListJSON {
+			if entries == nil {
+				entries = []trash.Entry{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(entries); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("Trash is empty")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "PROJECT\tNAME\tBRANCH\tDELETED\tEXPIRES\tSAVED\tID\n")
+		for _, e := range entries {
+			saved := fmt.Sprintf("%d file%s", len(e.Files), plural(len(e.Files)))
+			if e.HasPatch {
+				saved = "changes, " + saved
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				e.Project, e.Name, e.Branch, formatAge(e.DeletedAt), e.ExpiresAt().Format("2006-01-02"), saved, e.ID)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	trashListCmd.Flags().BoolVar(&trashListAll, "all", false, "list deleted worktrees across all projects")
+	trashListCmd.Flags().BoolVar(&trashListJSON, "json", false, "output JSON for scripting")
+
+	trashCmd.AddCommand(trashListCmd)
+}
@@ -0,0 +1,61 @@
+package worktree
+
+import (
+	"fmt"
+
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+)
+
+// repositoryName returns the name the current repository's worktrees are
+// filed under. It fails when that worktree directory belongs to another
+// repository of the same name, so no command acts on the other one's
+// worktrees.
+func repositoryName() (string, error) {
+	repoName, err := git.GetRepositoryName()
+	if err != nil {
+		return "", err
+	}
+	if err := git.CheckProjectOwner(repoName); err != nil {
+		return "", err
+	}
+	return repoName, nil
+}
+
+// newWorktreeDir returns the directory, relative to the repository's
+// worktree directory, of a new worktree called name, following the
+// worktree_naming scheme. A directory that's taken is an error, so wt new
+// stops before creating anything; the hashed scheme instead appends a hash
+// of identity (the branch, or the ref of a detached worktree). The
+// repository's worktree directory is claimed first, so one already used by
+// another repository of the same name is refused too.
+func newWorktreeDir(repoName, name, identity string) (string, error) {
+	if err := git.RegisterProject(repoName); err != nil {
+		return "", err
+	}
+
+	scheme := config.GetWorktreeNaming()
+	switch scheme {
+	case git.NamingFlat, git.NamingNested, git.NamingHashed:
+	default:
+		return "", fmt.Errorf("unknown worktree_naming '%s' (expected 'flat', 'nested' or 'hashed')", scheme)
+	}
+
+	dir := git.WorktreeDirName(name, scheme)
+	if dir == "" {
+		return "", fmt.Errorf("'%s' has nothing to name a worktree directory after", name)
+	}
+	err := git.WorktreeDirConflict(repoName, dir)
+	if err == nil {
+		return dir, nil
+	}
+	if scheme != git.NamingHashed {
+		return "", fmt.Errorf("%v; pass another name or set worktree_naming = \"hashed\"", err)
+	}
+
+	hashed := git.HashedWorktreeDirName(dir, identity)
+	if err := git.WorktreeDirConflict(repoName, hashed); err != nil {
+		return "", err
+	}
+	return hashed, nil
+}
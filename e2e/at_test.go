@@ -0,0 +1,81 @@
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewDetachedAtTag(t *testing.T) {
+	e := newEnv(t)
+	tagged := e.git(e.repo, "rev-parse", "origin/main")
+	e.git(e.seed, "push", "origin", tagged+":refs/tags/v1.0")
+
+	// The tag was published after the clone, so wt has to fetch it
+	e.mustWT("new", "repro", "--at", "v1.0", "--detach", "--no-tmux", "--no-editor")
+
+	path := e.worktreePath("repro")
+	if got := e.git(path, "rev-parse", "HEAD"); got != tagged {
+		t.Errorf("worktree is at %s, want v1.0 (%s)", got, tagged)
+	}
+	if got := e.git(path, "branch", "--show-current"); got != "" {
+		t.Errorf("worktree is on branch %q, want detached HEAD", got)
+	}
+
+	short := tagged[:7]
+	if r := e.mustWT("list"); !strings.Contains(r.stdout, "repro -> "+path+" (detached@"+short+")") {
+		t.Errorf("list doesn't show the detached worktree:\n%s", r.stdout)
+	}
+
+	var entries []struct {
+		Name     string `json:"name"`
+		Branch   string `json:"branch"`
+		Detached bool   `json:"detached"`
+	}
+	r := e.mustWT("list", "--json")
+	if err := json.Unmarshal([]byte(r.stdout), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, r.stdout)
+	}
+	if len(entries) != 1 || !entries[0].Detached || entries[0].Branch != "detached@"+short {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	// Detached worktrees have no branch to delete
+	e.mustWT("delete", "repro", "--force", "--delete-branch")
+}
+
+func TestNewBranchAtCommit(t *testing.T) {
+	e := newEnv(t)
+	commit := e.git(e.repo, "rev-parse", "origin/feature/login")
+
+	e.mustWT("new", "hotfix", "--at", commit, "--no-tmux", "--no-editor")
+
+	path := e.worktreePath("hotfix")
+	if got := e.git(path, "branch", "--show-current"); got != "hotfix" {
+		t.Errorf("worktree is on %q, want hotfix", got)
+	}
+	if got := e.git(path, "rev-parse", "HEAD"); got != commit {
+		t.Errorf("hotfix starts at %s, want %s", got, commit)
+	}
+
+	// --at names the start point explicitly, so an existing branch is an error
+	r := e.wt("new", "hotfix", "--at", commit, "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "already exists") {
+		t.Errorf("expected an error for an existing branch, got %v:\n%s", r.err, r.stderr)
+	}
+}
+
+func TestNewAtFlagValidation(t *testing.T) {
+	e := newEnv(t)
+
+	for _, args := range [][]string{
+		{"new", "x", "--detach"},
+		{"new", "x", "--at", "main", "--base", "main"},
+		{"new", "x", "--at", "main", "--from", "main"},
+		{"new", "x", "--at", "no-such-ref", "--detach", "--no-fetch"},
+	} {
+		if r := e.wt(args...); r.err == nil {
+			t.Errorf("wt %s succeeded", strings.Join(args, " "))
+		}
+	}
+}
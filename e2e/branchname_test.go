@@ -0,0 +1,43 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewAppliesBranchTemplate(t *testing.T) {
+	e := newEnv(t)
+	e.config("branch_template = \"{user}/{ticket}-{slug}/{date}\"\nbranch_user = \"ada\"\n")
+
+	e.mustWT("new", "Fix login bug", "--ticket", "ABC-12", "--no-tmux", "--no-editor")
+
+	want := "ada/ABC-12-fix-login-bug/" + time.Now().Format("2006-01-02")
+	if got := e.git(e.worktreePath("fix-login-bug"), "rev-parse", "--abbrev-ref", "HEAD"); got != want {
+		t.Errorf("worktree is on %q, want %q", got, want)
+	}
+
+	r := e.wt("new", "other", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "--ticket") {
+		t.Errorf("expected a missing ticket error, got %v:\n%s", r.err, r.stderr)
+	}
+}
+
+func TestNewRejectsBranchNamesBeforeCreatingAnything(t *testing.T) {
+	e := newEnv(t)
+	e.config("branch_pattern = \"^[a-z]+/[A-Z]+-[0-9]+-\"\n")
+
+	r := e.wt("new", "feat", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "doesn't match branch_pattern") {
+		t.Errorf("expected a pattern error, got %v:\n%s", r.err, r.stderr)
+	}
+
+	r = e.wt("new", "ada/ABC-1-bad..name", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "isn't a valid branch name") {
+		t.Errorf("expected a ref-format error, got %v:\n%s", r.err, r.stderr)
+	}
+
+	if branches := e.git(e.repo, "branch", "--list"); strings.Contains(branches, "feat") || strings.Contains(branches, "bad") {
+		t.Errorf("branches created:\n%s", branches)
+	}
+}
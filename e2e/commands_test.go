@@ -0,0 +1,269 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewCreatesWorktreeAndOpensEditorInSession(t *testing.T) {
+	e := newEnv(t)
+
+	e.mustWT("new", "feat")
+
+	path := e.worktreePath("feat")
+	if got := e.git(path, "rev-parse", "--abbrev-ref", "HEAD"); got != "feat" {
+		t.Errorf("worktree is on %q, want feat", got)
+	}
+	if got, want := e.git(path, "rev-parse", "HEAD"), e.git(e.repo, "rev-parse", "origin/main"); got != want {
+		t.Errorf("feat starts at %s, want origin/main (%s)", got, want)
+	}
+
+	e.assertLogged("tmux",
+		"new-session -d -s app-feat -c "+path+" fake-editor "+path,
+		"switch-client -t app-feat",
+	)
+	// The editor must run inside the session, in the worktree
+	e.assertLogged("editor", path+"|"+path)
+}
+
+func TestNewWithoutTmuxOrEditor(t *testing.T) {
+	e := newEnv(t)
+
+	e.mustWT("new", "quiet", "--no-tmux", "--no-editor")
+
+	if _, err := os.Stat(e.worktreePath("quiet")); err != nil {
+		t.Fatalf("worktree not created: %v", err)
+	}
+	if log := e.log("tmux"); log != nil {
+		t.Errorf("tmux invoked: %q", log)
+	}
+	if log := e.log("editor"); log != nil {
+		t.Errorf("editor invoked: %q", log)
+	}
+}
+
+func TestNewFromRemoteBranch(t *testing.T) {
+	e := newEnv(t)
+
+	e.mustWT("new", "--from", "feature/login", "--no-editor")
+
+	path := e.worktreePath("feature_login")
+	if got := e.git(path, "rev-parse", "--abbrev-ref", "HEAD"); got != "feature/login" {
+		t.Errorf("worktree is on %q, want feature/login", got)
+	}
+	if got := e.git(path, "rev-parse", "--abbrev-ref", "@{upstream}"); got != "origin/feature/login" {
+		t.Errorf("upstream is %q, want origin/feature/login", got)
+	}
+	if _, err := os.Stat(path + "/login.go"); err != nil {
+		t.Errorf("branch content missing: %v", err)
+	}
+	e.assertLogged("tmux",
+		"new-session -d -s app-feature_login -c "+path,
+		"switch-client -t app-feature_login",
+	)
+}
+
+func TestOpenCreatesThenReusesSession(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+	path := e.worktreePath("feat")
+
+	e.mustWT("open")
+	e.assertLogged("tmux",
+		"new-session -d -s app-feat -c "+path+" fake-editor "+path,
+		"switch-client -t app-feat",
+	)
+
+	// A second open reuses the session and sends the editor to its first pane
+	e.mustWT("open")
+	e.assertLogged("tmux", "send-keys -t app-feat:0.0 fake-editor "+path+" Enter")
+	if got := e.sessions(); len(got) != 1 || got[0] != "app-feat" {
+		t.Errorf("sessions = %q, want [app-feat]", got)
+	}
+	if got := e.log("editor"); len(got) != 2 {
+		t.Errorf("editor opened %d times, want 2: %q", len(got), got)
+	}
+}
+
+func TestListJSON(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "one", "--no-tmux", "--no-editor")
+	e.mustWT("new", "--from", "feature/login", "--no-tmux", "--no-editor")
+
+	r := e.mustWT("list", "--json")
+
+	var entries []struct {
+		Project string `json:"project"`
+		Name    string `json:"name"`
+		Path    string `json:"path"`
+		Branch  string `json:"branch"`
+		Head    string `json:"head"`
+		Locked  bool   `json:"locked"`
+	}
+	if err := json.Unmarshal([]byte(r.stdout), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, r.stdout)
+	}
+
+	want := map[string]string{"one": "one", "feature_login": "feature/login"}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d:\n%s", len(entries), len(want), r.stdout)
+	}
+	for _, entry := range entries {
+		if entry.Project != "app" || entry.Branch != want[entry.Name] || entry.Path != e.worktreePath(entry.Name) {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+		if entry.Head != e.git(entry.Path, "rev-parse", "HEAD") {
+			t.Errorf("%s: head %s doesn't match the worktree", entry.Name, entry.Head)
+		}
+	}
+}
+
+func TestDeleteRemovesWorktreeAndSession(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feat", "--no-editor")
+	path := e.worktreePath("feat")
+
+	e.mustWT("delete", "feat", "--force")
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("worktree directory still exists: %v", err)
+	}
+	if list := e.git(e.repo, "worktree", "list"); strings.Contains(list, path) {
+		t.Errorf("git still lists the worktree:\n%s", list)
+	}
+	e.assertLogged("tmux", "kill-session -t app-feat")
+	if got := e.sessions(); len(got) != 0 {
+		t.Errorf("sessions left: %q", got)
+	}
+	// The branch is kept by default
+	e.git(e.repo, "rev-parse", "--verify", "refs/heads/feat")
+}
+
+func TestDeleteRefusesUnsavedWork(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+	path := e.worktreePath("feat")
+	e.writeFile(path+"/notes.txt", "wip\n")
+
+	r := e.wt("delete", "feat", "--force")
+	if r.err == nil {
+		t.Fatal("delete succeeded despite untracked files")
+	}
+	if !strings.Contains(r.stderr, "unsaved work") {
+		t.Errorf("stderr doesn't mention unsaved work:\n%s", r.stderr)
+	}
+	if _, err := os.Stat(path + "/notes.txt"); err != nil {
+		t.Errorf("untracked file lost: %v", err)
+	}
+
+	e.mustWT("delete", "feat", "--force", "--discard-changes")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("worktree directory still exists: %v", err)
+	}
+}
+
+func TestProtectionFailsClosedOnBrokenConfig(t *testing.T) {
+	e := newEnv(t)
+	e.config("protected = [\"release-*\"]\n")
+	e.mustWT("new", "release-1", "--no-tmux", "--no-editor")
+
+	if r := e.wt("delete", "release-1", "--force"); r.err == nil || !strings.Contains(r.stderr, "protected by pattern") {
+		t.Fatalf("delete of a protected worktree should fail (%v):\n%s", r.err, r.stderr)
+	}
+
+	e.writeFile(filepath.Join(e.repo, ".wt.toml"), "bad = [\n")
+	for _, args := range [][]string{{"delete", "release-1", "--force"}, {"prune", "--dry-run"}, {"clean", "--merged", "--force"}} {
+		if r := e.wt(args...); r.err == nil || !strings.Contains(r.stderr, "can't check protected patterns") {
+			t.Errorf("wt %s should refuse with a broken config (%v):\n%s%s", strings.Join(args, " "), r.err, r.stdout, r.stderr)
+		}
+	}
+	if _, err := os.Stat(e.worktreePath("release-1")); err != nil {
+		t.Errorf("protected worktree removed: %v", err)
+	}
+}
+
+func TestPruneKeepsProtectedNestedOrphan(t *testing.T) {
+	e := newEnv(t)
+	e.config("protected = [\"release/*\"]\nworktree_naming = \"nested\"\n")
+	e.mustWT("new", "release/2.0", "--no-tmux", "--no-editor")
+	orphan := e.worktreePath("release/1.0")
+	e.writeFile(filepath.Join(orphan, "notes.txt"), "keep\n")
+
+	r := e.mustWT("prune", "--force")
+	if !strings.Contains(r.stdout, "Skipping protected: "+orphan) {
+		t.Errorf("prune didn't skip the protected orphan:\n%s", r.stdout)
+	}
+	if _, err := os.Stat(orphan); err != nil {
+		t.Errorf("protected orphan removed: %v", err)
+	}
+}
+
+func TestCleanKeepsGoneBranchWithNewCommits(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "gone", "--no-tmux", "--no-editor")
+	path := e.worktreePath("gone")
+	e.writeFile(filepath.Join(path, "a.txt"), "a\n")
+	e.git(path, "add", "a.txt")
+	e.git(path, "commit", "-m", "Add a")
+	e.git(path, "push", "-u", "origin", "gone")
+	e.git(path, "push", "origin", "--delete", "gone")
+	e.git(path, "fetch", "--prune")
+
+	// Work continues after the remote branch was deleted
+	e.writeFile(filepath.Join(path, "b.txt"), "b\n")
+	e.git(path, "add", "b.txt")
+	e.git(path, "commit", "-m", "Add b")
+
+	r := e.mustWT("clean", "--merged", "--force")
+	if !strings.Contains(r.stdout, "Skipping 'gone': upstream is gone but it has 2 commits") {
+		t.Errorf("clean didn't skip the gone branch:\n%s", r.stdout)
+	}
+	e.git(e.repo, "rev-parse", "--verify", "refs/heads/gone")
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("worktree removed: %v", err)
+	}
+}
+
+func TestDeleteKeepsRemoteDefaultAndProtectedBranches(t *testing.T) {
+	e := newEnv(t)
+	e.config("protected = [\"release/*\"]\n")
+	e.mustWT("new", "tracks-main", "--no-tmux", "--no-editor")
+	e.git(e.worktreePath("tracks-main"), "branch", "--set-upstream-to", "origin/main")
+
+	r := e.mustWT("delete", "tracks-main", "--force", "--delete-branch", "--delete-remote-branch")
+	if !strings.Contains(r.stdout, "Not deleting remote branch 'origin/main': it's the remote's default branch") {
+		t.Errorf("delete didn't refuse origin/main:\n%s", r.stdout)
+	}
+	e.git(e.origin, "rev-parse", "--verify", "refs/heads/main")
+
+	e.git(e.repo, "push", "origin", "main:release/1")
+	e.mustWT("new", "rel", "--no-tmux", "--no-editor")
+	e.git(e.worktreePath("rel"), "fetch", "origin")
+	e.git(e.worktreePath("rel"), "branch", "--set-upstream-to", "origin/release/1")
+
+	r = e.mustWT("delete", "rel", "--force", "--delete-remote-branch")
+	if !strings.Contains(r.stdout, "protected by pattern 'release/*'") {
+		t.Errorf("delete didn't refuse the protected remote branch:\n%s", r.stdout)
+	}
+	e.git(e.origin, "rev-parse", "--verify", "refs/heads/release/1")
+}
+
+func TestSyncSkipsWorktreeWithUntrackedFiles(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+	path := e.worktreePath("feat")
+	e.writeFile(filepath.Join(path, "notes.txt"), "draft\n")
+	head := e.git(path, "rev-parse", "HEAD")
+
+	e.push("main", "refs/heads/main", "upstream.go")
+	r := e.mustWT("sync")
+	if !strings.Contains(r.stdout, "skipped-dirty") {
+		t.Errorf("sync didn't skip the worktree with untracked files:\n%s", r.stdout)
+	}
+	if got := e.git(path, "rev-parse", "HEAD"); got != head {
+		t.Errorf("worktree moved to %s despite untracked files", got)
+	}
+}
@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribeRecordsWhatWorktreesAreFor(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "tmp-fix-2", "--description", "Flaky checkout test", "--ticket", "ABC-12", "--no-tmux", "--no-editor")
+
+	r := e.mustWT("list")
+	if !strings.Contains(r.stdout, "tmp-fix-2 -> "+e.worktreePath("tmp-fix-2")+" — ABC-12: Flaky checkout test") {
+		t.Errorf("list doesn't show the description:\n%s", r.stdout)
+	}
+
+	e.mustWT("describe", "tmp-fix-2", "--description", "Retry checkout on timeout", "--ticket", "")
+	r = e.mustWT("describe", "tmp-fix-2")
+	for _, want := range []string{"Description: Retry checkout on timeout", "Base:        origin/main", "Created:     "} {
+		if !strings.Contains(r.stdout, want) {
+			t.Errorf("describe output lacks %q:\n%s", want, r.stdout)
+		}
+	}
+	if strings.Contains(r.stdout, "Ticket:") {
+		t.Errorf("ticket wasn't cleared:\n%s", r.stdout)
+	}
+
+	var entries []struct {
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		Base        string     `json:"base"`
+		CreatedAt   *time.Time `json:"created_at"`
+	}
+	r = e.mustWT("list", "--json")
+	if err := json.Unmarshal([]byte(r.stdout), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, r.stdout)
+	}
+	if len(entries) != 1 || entries[0].Description != "Retry checkout on timeout" || entries[0].Base != "origin/main" || entries[0].CreatedAt == nil {
+		t.Errorf("unexpected entries:\n%s", r.stdout)
+	}
+
+	// The record lives outside the worktree
+	if status := e.git(e.worktreePath("tmp-fix-2"), "status", "--porcelain"); status != "" {
+		t.Errorf("worktree isn't clean:\n%s", status)
+	}
+	if _, err := os.Stat(filepath.Join(e.baseDir, ".wt", "worktrees", "app", "tmp-fix-2.json")); err != nil {
+		t.Errorf("record not stored under the base directory: %v", err)
+	}
+}
+
+func TestDescribeWorktreeCreatedOutsideWT(t *testing.T) {
+	e := newEnv(t)
+	e.git(e.repo, "worktree", "add", "-b", "manual", e.worktreePath("manual"))
+
+	e.mustWT("describe", "manual", "--description", "Made by hand")
+
+	if r := e.mustWT("list"); !strings.Contains(r.stdout, "— Made by hand") {
+		t.Errorf("list doesn't show the description:\n%s", r.stdout)
+	}
+}
@@ -0,0 +1,322 @@
+// Package e2e runs the wt binary against throwaway git repositories, with
+// stub tmux and editor executables that log how they were invoked.
+package e2e
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// Not used directly: depending on the commands makes go test's cache
+	// notice changes to the binary under test
+	_ "github.com/todoengineering/wt/cmd/worktree"
+)
+
+// binDir holds the wt binary and the tmux and editor stubs.
+var binDir string
+
+// tmuxStub emulates the tmux commands wt uses. Sessions are kept in a file so
+// has-session and list-sessions see what new-session created, and commands
+// given to new-session or send-keys are run, the way a pane would run them.
+const tmuxStub = `#!/bin/sh
+printf '%s\n' "$*" >> "$WT_E2E_LOGS/tmux.log"
+sessions="$WT_E2E_LOGS/tmux-sessions"
+touch "$sessions"
+
+cmd=$1
+shift
+case "$cmd" in
+has-session)
+	grep -qxF "$2" "$sessions"
+	exit
+	;;
+list-sessions)
+	cat "$sessions"
+	;;
+new-session)
+	name=
+	dir=.
+	while [ $# -gt 0 ]; do
+		case "$1" in
+		-d) shift ;;
+		-s) name=$2; shift 2 ;;
+		-c) dir=$2; shift 2 ;;
+		-n) shift 2 ;;
+		*) break ;;
+		esac
+	done
+	echo "$name" >> "$sessions"
+	if [ $# -gt 0 ]; then
+		cd "$dir" && sh -c "$1"
+	fi
+	;;
+send-keys)
+	sh -c "$3"
+	;;
+kill-session)
+	grep -vxF "$2" "$sessions" > "$sessions.tmp"
+	mv "$sessions.tmp" "$sessions"
+	;;
+rename-session)
+	grep -vxF "$2" "$sessions" > "$sessions.tmp"
+	echo "$3" >> "$sessions.tmp"
+	mv "$sessions.tmp" "$sessions"
+	;;
+esac
+exit 0
+`
+
+// editorStub logs the directory it was started in and its arguments.
+const editorStub = `#!/bin/sh
+printf '%s|%s\n' "$PWD" "$*" >> "$WT_E2E_LOGS/editor.log"
+`
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		fmt.Println("skipping end-to-end tests in short mode")
+		os.Exit(0)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		fmt.Println("skipping end-to-end tests: git not installed")
+		os.Exit(0)
+	}
+
+	dir, err := os.MkdirTemp("", "wt-e2e")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	binDir = filepath.Join(dir, "bin")
+
+	code := 1
+	if err := setupBin(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		code = m.Run()
+	}
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// setupBin builds wt and installs the stubs into binDir.
+func setupBin() error {
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return err
+	}
+
+	build := exec.Command("go", "build", "-o", filepath.Join(binDir, "wt"), "..")
+	if output, err := build.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to build wt: %v\n%s", err, output)
+	}
+
+	if err := os.WriteFile(filepath.Join(binDir, "tmux"), []byte(tmuxStub), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(binDir, "fake-editor"), []byte(editorStub), 0755)
+}
+
+// env is an isolated environment: a clone of a bare origin repository, plus
+// config, worktree and log directories, all under a per-test temp dir.
+type env struct {
+	t       *testing.T
+	root    string
+	repo    string
+	origin  string
+	seed    string
+	baseDir string
+	logs    string
+	vars    []string
+}
+
+// newEnv creates a repository "app" cloned from a bare origin with a main
+// branch and a remote-only feature/login branch.
+func newEnv(t *testing.T) *env {
+	t.Helper()
+
+	// Resolve symlinks (e.g. /tmp on macOS) so paths match git's output
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := &env{
+		t:       t,
+		root:    root,
+		repo:    filepath.Join(root, "src", "app"),
+		origin:  filepath.Join(root, "origin.git"),
+		seed:    filepath.Join(root, "seed"),
+		baseDir: filepath.Join(root, "worktrees"),
+		logs:    filepath.Join(root, "logs"),
+	}
+	for _, d := range []string{e.logs, filepath.Join(root, "home"), filepath.Join(root, "config")} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e.vars = []string{
+		"PATH=" + binDir + string(os.PathListSeparator) + os.Getenv("PATH"),
+		"HOME=" + filepath.Join(root, "home"),
+		"XDG_CONFIG_HOME=" + filepath.Join(root, "config"),
+		"WORKTREE_BASE_DIR=" + e.baseDir,
+		"WT_E2E_LOGS=" + e.logs,
+		"EDITOR=fake-editor",
+		// Pretend to run inside tmux so sessions are switched to, not attached
+		"TMUX=/tmp/wt-e2e-tmux,1,0",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=wt", "GIT_AUTHOR_EMAIL=wt@example.com",
+		"GIT_COMMITTER_NAME=wt", "GIT_COMMITTER_EMAIL=wt@example.com",
+	}
+
+	seed := e.seed
+	e.git(root, "init", "--bare", "-b", "main", e.origin)
+	e.git(root, "init", "-b", "main", seed)
+	e.writeFile(filepath.Join(seed, "README.md"), "app\n")
+	e.git(seed, "add", "README.md")
+	e.git(seed, "commit", "-m", "Initial commit")
+	e.git(seed, "remote", "add", "origin", e.origin)
+	e.git(seed, "push", "origin", "main")
+	e.git(seed, "checkout", "-b", "feature/login")
+	e.writeFile(filepath.Join(seed, "login.go"), "package app\n")
+	e.git(seed, "add", "login.go")
+	e.git(seed, "commit", "-m", "Add login")
+	e.git(seed, "push", "origin", "feature/login")
+
+	e.git(root, "clone", e.origin, e.repo)
+	return e
+}
+
+// push commits file to the seed repository on top of parent and pushes the
+// commit to ref on origin, returning the commit. Use it to publish refs such
+// as refs/pull/1/head the way a hosting service would.
+func (e *env) push(parent, ref, file string) string {
+	e.t.Helper()
+	e.git(e.seed, "checkout", "--quiet", "--detach", parent)
+	e.writeFile(filepath.Join(e.seed, file), file+"\n")
+	e.git(e.seed, "add", file)
+	e.git(e.seed, "commit", "-m", "Add "+file)
+	e.git(e.seed, "push", "--force", "origin", "HEAD:"+ref)
+	return e.git(e.seed, "rev-parse", "HEAD")
+}
+
+// config writes the wt config file.
+func (e *env) config(content string) {
+	e.writeFile(filepath.Join(e.root, "config", "wt", "config.toml"), content)
+}
+
+func (e *env) writeFile(path, content string) {
+	e.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		e.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		e.t.Fatal(err)
+	}
+}
+
+// git runs git in dir and returns its trimmed output, failing the test on error.
+func (e *env) git(dir string, args ...string) string {
+	e.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), e.vars...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		e.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// result is the outcome of running wt.
+type result struct {
+	stdout string
+	stderr string
+	err    error
+}
+
+// wt runs the wt binary in the repository with args.
+func (e *env) wt(args ...string) result {
+	e.t.Helper()
+	return e.wtIn(e.repo, args...)
+}
+
+// wtIn runs the wt binary in dir with args.
+func (e *env) wtIn(dir string, args ...string) result {
+	e.t.Helper()
+	cmd := exec.Command(filepath.Join(binDir, "wt"), args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), e.vars...)
+	// Answer prompts with nothing rather than blocking on the terminal
+	cmd.Stdin = strings.NewReader("")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return result{stdout: stdout.String(), stderr: stderr.String(), err: err}
+}
+
+// mustWT runs wt and fails the test if it exits with an error.
+func (e *env) mustWT(args ...string) result {
+	e.t.Helper()
+	r := e.wt(args...)
+	if r.err != nil {
+		e.t.Fatalf("wt %s: %v\nstdout:\n%s\nstderr:\n%s", strings.Join(args, " "), r.err, r.stdout, r.stderr)
+	}
+	return r
+}
+
+// worktreePath returns where wt puts worktree name of the app repository.
+func (e *env) worktreePath(name string) string {
+	return filepath.Join(e.baseDir, "app", name)
+}
+
+// log returns the lines a stub logged ("tmux" or "editor").
+func (e *env) log(stub string) []string {
+	e.t.Helper()
+	data, err := os.ReadFile(filepath.Join(e.logs, stub+".log"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// sessions returns the tmux sessions the stub knows about.
+func (e *env) sessions() []string {
+	e.t.Helper()
+	data, err := os.ReadFile(filepath.Join(e.logs, "tmux-sessions"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+// assertLogged fails the test unless the stub logged each of lines.
+func (e *env) assertLogged(stub string, lines ...string) {
+	e.t.Helper()
+	logged := e.log(stub)
+	for _, want := range lines {
+		found := false
+		for _, line := range logged {
+			if line == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			e.t.Errorf("%s was not invoked with %q; invocations:\n  %s", stub, want, strings.Join(logged, "\n  "))
+		}
+	}
+}
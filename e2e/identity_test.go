@@ -0,0 +1,140 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cloneAs clones origin to another directory also called "app", the way
+// work/app and oss/app would be.
+func (e *env) cloneAs(dir string) string {
+	e.t.Helper()
+	path := filepath.Join(e.root, dir, "app")
+	e.git(e.root, "clone", e.origin, path)
+	return path
+}
+
+func TestNewRefusesSharedRepositoryName(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "one", "--no-tmux", "--no-editor")
+
+	other := e.cloneAs("oss")
+	r := e.wtIn(other, "new", "two", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "already belongs to the repository at "+e.repo) {
+		t.Fatalf("new in a second 'app' should fail (%v):\n%s%s", r.err, r.stdout, r.stderr)
+	}
+	if _, err := os.Stat(e.worktreePath("two")); !os.IsNotExist(err) {
+		t.Errorf("worktree two was created anyway")
+	}
+
+	// With repo_layout = "path" each clone gets its own directory
+	e.config("repo_layout = \"path\"\n")
+	e.mustWT("new", "three", "--no-tmux", "--no-editor")
+	if r := e.wtIn(other, "new", "two", "--no-tmux", "--no-editor"); r.err != nil {
+		t.Fatalf("new with repo_layout = path: %v\n%s%s", r.err, r.stdout, r.stderr)
+	}
+	dir := filepath.Join(e.baseDir, strings.TrimPrefix(filepath.ToSlash(other), "/"), "two")
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("worktree two not at %s: %v", dir, err)
+	}
+}
+
+func TestCommandsRefuseSharedRepositoryName(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "one", "--no-tmux", "--no-editor")
+
+	other := e.cloneAs("oss")
+	for _, args := range [][]string{
+		{"list"}, {"status"}, {"sync"}, {"prune", "--force"}, {"clean", "--merged", "--force"},
+		{"delete", "one", "--force"}, {"lock", "one"}, {"open", "one", "--no-tmux", "--no-editor"},
+	} {
+		r := e.wtIn(other, args...)
+		if r.err == nil || !strings.Contains(r.stderr, "already belongs to the repository at "+e.repo) || !strings.Contains(r.stderr, "wt migrate") {
+			t.Errorf("wt %s in a second 'app' should fail (%v):\n%s%s", strings.Join(args, " "), r.err, r.stdout, r.stderr)
+		}
+	}
+	if _, err := os.Stat(e.worktreePath("one")); err != nil {
+		t.Errorf("worktree one removed: %v", err)
+	}
+}
+
+func TestMigrateToRemoteLayout(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "one", "--no-tmux", "--no-editor", "--description", "First")
+	e.mustWT("lock", "one")
+
+	e.git(e.repo, "remote", "set-url", "origin", "git@github.com:acme/app.git")
+	e.config("repo_layout = \"remote\"\n")
+	newPath := filepath.Join(e.baseDir, "github.com", "acme", "app", "one")
+
+	r := e.mustWT("migrate", "--dry-run")
+	if !strings.Contains(r.stdout, newPath) {
+		t.Errorf("dry run doesn't report the move:\n%s", r.stdout)
+	}
+	if _, err := os.Stat(e.worktreePath("one")); err != nil {
+		t.Fatalf("dry run moved the worktree: %v", err)
+	}
+
+	e.mustWT("migrate")
+	if _, err := os.Stat(filepath.Join(e.baseDir, "app")); !os.IsNotExist(err) {
+		t.Errorf("old project directory left behind")
+	}
+	if got := e.git(newPath, "rev-parse", "--abbrev-ref", "HEAD"); got != "one" {
+		t.Errorf("moved worktree is on %q, want one", got)
+	}
+	if list := e.git(e.repo, "worktree", "list", "--porcelain"); !strings.Contains(list, "worktree "+newPath+"\n") || !strings.Contains(list, "locked") {
+		t.Errorf("git doesn't know the new location or lost the lock:\n%s", list)
+	}
+	if r := e.mustWT("describe", "one"); !strings.Contains(r.stdout, "First") {
+		t.Errorf("record wasn't moved:\n%s", r.stdout)
+	}
+	if r := e.wtIn(e.root, "list", "--all"); r.err != nil || !strings.Contains(r.stdout, "github.com/acme/app") {
+		t.Errorf("list outside the repository misses the project (%v):\n%s%s", r.err, r.stdout, r.stderr)
+	}
+	if r := e.mustWT("migrate"); !strings.Contains(r.stdout, "Nothing to migrate") {
+		t.Errorf("second migrate moved something:\n%s", r.stdout)
+	}
+}
+
+func TestPruneKeepsWorktreesOfSameNamedRepository(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "mine", "--no-tmux", "--no-editor")
+
+	// Another clone called "app" puts worktrees into the same directory
+	other := e.cloneAs("oss")
+	foreign := e.worktreePath("feat1")
+	nested := e.worktreePath("nested/feat2")
+	e.git(other, "worktree", "add", "-b", "feat1", foreign)
+	e.git(other, "worktree", "add", "-b", "feat2", nested)
+	e.writeFile(filepath.Join(foreign, "wip.go"), "package app\n")
+
+	// A worktree git dropped from this repository is a real leftover
+	dropped := e.worktreePath("dropped")
+	e.git(e.repo, "worktree", "add", "-b", "dropped", dropped)
+	if err := os.RemoveAll(filepath.Join(e.repo, ".git", "worktrees", "dropped")); err != nil {
+		t.Fatal(err)
+	}
+	leftover := e.worktreePath("leftover")
+	e.writeFile(filepath.Join(leftover, "junk"), "junk\n")
+
+	r := e.mustWT("prune", "--dry-run")
+	for _, dir := range []string{foreign, nested} {
+		if !strings.Contains(r.stdout, "doesn't own: "+dir) {
+			t.Errorf("prune doesn't report %s as foreign:\n%s", dir, r.stdout)
+		}
+	}
+
+	e.mustWT("prune", "--force")
+	for _, path := range []string{filepath.Join(foreign, "wip.go"), nested, e.worktreePath("mine")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("prune removed %s: %v", path, err)
+		}
+	}
+	for _, dir := range []string{dropped, leftover} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("orphaned %s left behind: %v", dir, err)
+		}
+	}
+}
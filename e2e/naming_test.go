@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewRefusesDirectoryCollisions(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feature/a_b", "--no-tmux", "--no-editor")
+
+	r := e.wt("new", "feature_a/b", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "already used by the worktree for branch 'feature/a_b'") {
+		t.Fatalf("expected a collision error, got %v:\n%s", r.err, r.stderr)
+	}
+	// Refused up front: the branch wasn't created
+	if branches := e.git(e.repo, "branch", "--list", "feature_a/b"); branches != "" {
+		t.Errorf("branch created despite the collision: %q", branches)
+	}
+}
+
+func TestHashedNamingAppendsHashOnCollision(t *testing.T) {
+	e := newEnv(t)
+	e.config("worktree_naming = \"hashed\"\n")
+	e.mustWT("new", "feature/a_b", "--no-tmux", "--no-editor")
+	e.mustWT("new", "feature_a/b", "--no-tmux", "--no-editor")
+
+	matches, _ := filepath.Glob(e.worktreePath("feature_a_b-*"))
+	if len(matches) != 1 {
+		t.Fatalf("hashed worktree directories = %q, want one", matches)
+	}
+	if got := e.git(matches[0], "rev-parse", "--abbrev-ref", "HEAD"); got != "feature_a/b" {
+		t.Errorf("hashed worktree is on %q, want feature_a/b", got)
+	}
+	if got := e.git(e.worktreePath("feature_a_b"), "rev-parse", "--abbrev-ref", "HEAD"); got != "feature/a_b" {
+		t.Errorf("flat worktree is on %q, want feature/a_b", got)
+	}
+}
+
+func TestNestedNaming(t *testing.T) {
+	e := newEnv(t)
+	e.config("worktree_naming = \"nested\"\n")
+
+	e.mustWT("new", "--from", "feature/login", "--no-tmux", "--no-editor")
+	path := e.worktreePath("feature/login")
+	if _, err := os.Stat(filepath.Join(path, "login.go")); err != nil {
+		t.Fatalf("nested worktree not created: %v", err)
+	}
+
+	// A worktree can't be created in a directory containing another one
+	r := e.wt("new", "feature", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "would contain the worktree") {
+		t.Errorf("expected a nesting error, got %v:\n%s", r.err, r.stderr)
+	}
+
+	if r := e.mustWT("list"); !strings.Contains(r.stdout, "feature/login") {
+		t.Errorf("list doesn't show feature/login:\n%s", r.stdout)
+	}
+	if r := e.mustWT("prune", "--dry-run"); strings.Contains(r.stdout, "Orphaned directories") {
+		t.Errorf("prune treats the parent directory as orphaned:\n%s", r.stdout)
+	}
+
+	e.mustWT("delete", "feature/login", "--force")
+	if _, err := os.Stat(e.worktreePath("feature")); !os.IsNotExist(err) {
+		t.Errorf("empty parent directory left behind: %v", err)
+	}
+}
+
+func TestPruneKeepsNestedWorktreeSession(t *testing.T) {
+	e := newEnv(t)
+	e.config("worktree_naming = \"nested\"\n")
+	e.mustWT("new", "work/login", "--from", "feature/login", "--no-editor")
+
+	sessions := e.sessions()
+	if len(sessions) != 1 {
+		t.Fatalf("sessions = %q, want one", sessions)
+	}
+	if r := e.mustWT("prune", "--dry-run"); strings.Contains(r.stdout, sessions[0]) {
+		t.Errorf("prune treats the live session %s as stale:\n%s", sessions[0], r.stdout)
+	}
+}
+
+func TestNestedAndFlatRecordsStayApart(t *testing.T) {
+	e := newEnv(t)
+	e.config("worktree_naming = \"nested\"\n")
+	e.mustWT("new", "feature/x", "--no-tmux", "--no-editor")
+	e.mustWT("new", "feature_x", "--no-tmux", "--no-editor", "--description", "important notes")
+
+	// feature/x has no description of its own
+	if r := e.mustWT("describe", "feature/x"); strings.Contains(r.stdout, "important notes") {
+		t.Errorf("feature/x shows feature_x's record:\n%s", r.stdout)
+	}
+
+	e.mustWT("delete", "feature/x", "--force")
+	if r := e.mustWT("describe", "feature_x"); !strings.Contains(r.stdout, "important notes") {
+		t.Errorf("deleting feature/x removed feature_x's record:\n%s", r.stdout)
+	}
+}
@@ -0,0 +1,114 @@
+package e2e
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewPullRequest(t *testing.T) {
+	e := newEnv(t)
+	head := e.push("main", "refs/pull/7/head", "review.go")
+
+	e.mustWT("new", "--pr", "7", "--no-tmux", "--no-editor")
+
+	path := e.worktreePath("pr_7")
+	if got := e.git(path, "rev-parse", "--abbrev-ref", "HEAD"); got != "pr/7" {
+		t.Errorf("worktree is on %q, want pr/7", got)
+	}
+	if got := e.git(path, "rev-parse", "HEAD"); got != head {
+		t.Errorf("worktree is at %s, want the pull request head %s", got, head)
+	}
+	if got := e.git(e.repo, "rev-parse", "refs/wt/pr/origin/7"); got != head {
+		t.Errorf("refs/wt/pr/origin/7 is %s, want %s", got, head)
+	}
+}
+
+func TestPullRequestHeadSurvivesPrune(t *testing.T) {
+	e := newEnv(t)
+	head := e.push("main", "refs/pull/7/head", "review.go")
+	branch := e.push("main", "refs/heads/pr/7", "unrelated.go")
+
+	e.mustWT("new", "--pr", "7", "--no-tmux", "--no-editor")
+	e.git(e.repo, "fetch", "--prune", "origin")
+
+	if got := e.git(e.repo, "rev-parse", "refs/wt/pr/origin/7"); got != head {
+		t.Errorf("refs/wt/pr/origin/7 is %s after fetch --prune, want %s", got, head)
+	}
+	if got := e.git(e.repo, "rev-parse", "refs/remotes/origin/pr/7"); got != branch {
+		t.Errorf("the remote's own pr/7 branch is %s, want %s", got, branch)
+	}
+}
+
+func TestNewPullRequestUpdate(t *testing.T) {
+	e := newEnv(t)
+	first := e.push("main", "refs/pull/7/head", "review.go")
+	e.mustWT("new", "--pr", "7", "--no-tmux", "--no-editor")
+	path := e.worktreePath("pr_7")
+
+	second := e.push(first, "refs/pull/7/head", "fixup.go")
+
+	// Without --update the existing branch is reused as is
+	e.mustWT("new", "--pr", "7", "--no-tmux", "--no-editor")
+	if got := e.git(path, "rev-parse", "HEAD"); got != first {
+		t.Errorf("worktree moved to %s without --update", got)
+	}
+
+	e.mustWT("new", "--pr", "7", "--update", "--no-tmux", "--no-editor")
+	if got := e.git(path, "rev-parse", "HEAD"); got != second {
+		t.Errorf("worktree is at %s, want the new head %s", got, second)
+	}
+	if _, err := os.Stat(path + "/fixup.go"); err != nil {
+		t.Errorf("worktree files not updated: %v", err)
+	}
+
+	// A force push can't be fast-forwarded
+	e.push("main", "refs/pull/7/head", "rewritten.go")
+	r := e.wt("new", "--pr", "7", "--update", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "force-pushed") {
+		t.Errorf("expected a force-push error, got %v:\n%s", r.err, r.stderr)
+	}
+	if got := e.git(path, "rev-parse", "HEAD"); got != second {
+		t.Errorf("worktree moved to %s after a failed update", got)
+	}
+}
+
+func TestSyncPicksUpPullRequestPushes(t *testing.T) {
+	e := newEnv(t)
+	first := e.push("main", "refs/pull/7/head", "review.go")
+	e.mustWT("new", "--pr", "7", "--no-tmux", "--no-editor")
+	path := e.worktreePath("pr_7")
+
+	second := e.push(first, "refs/pull/7/head", "fixup.go")
+	r := e.mustWT("sync")
+	if got := e.git(path, "rev-parse", "HEAD"); got != second {
+		t.Errorf("worktree is at %s after sync, want the new head %s:\n%s", got, second, r.stdout)
+	}
+}
+
+func TestNewPullRequestConfiguredRefspec(t *testing.T) {
+	e := newEnv(t)
+	e.config("pr_refspec = \"refs/merge-requests/{number}/head\"\n")
+	head := e.push("main", "refs/merge-requests/12/head", "mr.go")
+
+	e.mustWT("new", "mr-12", "--pr", "12", "--no-tmux", "--no-editor")
+
+	if got := e.git(e.worktreePath("mr-12"), "rev-parse", "HEAD"); got != head {
+		t.Errorf("worktree is at %s, want the merge request head %s", got, head)
+	}
+}
+
+func TestNewPullRequestMissing(t *testing.T) {
+	e := newEnv(t)
+
+	r := e.wt("new", "--pr", "99", "--no-tmux", "--no-editor")
+	if r.err == nil {
+		t.Fatal("expected an error for a missing pull request")
+	}
+	if !strings.Contains(r.stderr, "refs/pull/99/head") {
+		t.Errorf("error doesn't name the missing ref:\n%s", r.stderr)
+	}
+	if _, err := os.Stat(e.worktreePath("pr_99")); !os.IsNotExist(err) {
+		t.Errorf("worktree created for a missing pull request")
+	}
+}
@@ -0,0 +1,45 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenDashReturnsToPreviousWorktree(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "one", "--no-tmux", "--no-editor")
+	e.mustWT("new", "two", "--no-tmux", "--no-editor")
+
+	// From the main repository, "-" is the last worktree used
+	r := e.mustWT("open", "-", "--no-tmux", "--no-editor")
+	if !strings.Contains(r.stdout, "Opening worktree: app/two") {
+		t.Errorf("open - didn't pick two:\n%s", r.stdout)
+	}
+
+	// Inside two, it's the one used before
+	r = e.wtIn(e.worktreePath("two"), "open", "-", "--no-tmux", "--no-editor")
+	if r.err != nil || !strings.Contains(r.stdout, "Opening worktree: app/one") {
+		t.Errorf("open - from two didn't pick one (%v):\n%s%s", r.err, r.stdout, r.stderr)
+	}
+
+	// A deleted worktree is skipped
+	e.mustWT("delete", "two", "--force")
+	r = e.mustWT("open", "-", "--no-tmux", "--no-editor")
+	if !strings.Contains(r.stdout, "Opening worktree: app/one") {
+		t.Errorf("open - didn't skip the deleted worktree:\n%s", r.stdout)
+	}
+}
+
+func TestPinMarksWorktree(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "one", "--no-tmux", "--no-editor")
+
+	e.mustWT("pin", "one")
+	if r := e.mustWT("describe", "one"); !strings.Contains(r.stdout, "Pinned") {
+		t.Errorf("describe doesn't show the pin:\n%s", r.stdout)
+	}
+	e.mustWT("unpin", "one")
+	if r := e.mustWT("describe", "one"); strings.Contains(r.stdout, "Pinned") {
+		t.Errorf("still pinned:\n%s", r.stdout)
+	}
+}
@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenameRejectsInvalidBranchUpFront(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feat", "--no-tmux", "--no-editor", "--description", "Work")
+
+	r := e.wt("rename", "feat", "renamed", "--branch", "bad..name")
+	if r.err == nil || !strings.Contains(r.stderr, "isn't a valid branch name") {
+		t.Fatalf("rename with an invalid branch should fail (%v):\n%s%s", r.err, r.stdout, r.stderr)
+	}
+	if _, err := os.Stat(e.worktreePath("feat")); err != nil {
+		t.Errorf("worktree moved despite the invalid branch: %v", err)
+	}
+	if r := e.mustWT("describe", "feat"); !strings.Contains(r.stdout, "Work") {
+		t.Errorf("record changed:\n%s", r.stdout)
+	}
+}
+
+func TestRenameNestedWorktree(t *testing.T) {
+	e := newEnv(t)
+	e.config("worktree_naming = \"nested\"\n")
+	e.mustWT("new", "feature/x", "--no-editor", "--description", "Nested work")
+	e.writeFile(filepath.Join(e.worktreePath("feature/x"), "wip.go"), "package app\n")
+
+	e.mustWT("rename", "feature/x", "y")
+
+	path := e.worktreePath("y")
+	if _, err := os.Stat(filepath.Join(path, "wip.go")); err != nil {
+		t.Fatalf("worktree not moved to %s: %v", path, err)
+	}
+	if _, err := os.Stat(e.worktreePath("feature")); !os.IsNotExist(err) {
+		t.Errorf("empty feature directory left behind: %v", err)
+	}
+	if r := e.mustWT("list"); strings.Contains(r.stdout, "feature/y") {
+		t.Errorf("list still shows the worktree nested:\n%s", r.stdout)
+	}
+	if r := e.mustWT("describe", "y"); !strings.Contains(r.stdout, "Nested work") {
+		t.Errorf("record didn't follow the rename:\n%s", r.stdout)
+	}
+	if got := e.sessions(); len(got) != 1 || got[0] != "app-y" {
+		t.Errorf("sessions = %q, want [app-y]", got)
+	}
+}
+
+func TestRenameRefusesTakenName(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "one", "--no-tmux", "--no-editor")
+	e.mustWT("new", "two", "--no-tmux", "--no-editor")
+
+	if r := e.wt("rename", "one", "two"); r.err == nil || !strings.Contains(r.stderr, "already used by the worktree") {
+		t.Errorf("rename onto another worktree should fail (%v):\n%s", r.err, r.stderr)
+	}
+	if _, err := os.Stat(e.worktreePath("one")); err != nil {
+		t.Errorf("worktree one moved: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenByName(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feature/login", "--no-tmux", "--no-editor")
+	e.mustWT("new", "docs", "--no-tmux", "--no-editor")
+
+	for _, identifier := range []string{"feature_login", "feature/login", "app/feature_login", "flogin"} {
+		r := e.mustWT("open", identifier, "--no-tmux", "--no-editor")
+		if !strings.Contains(r.stdout, "Opening worktree: app/feature_login") {
+			t.Errorf("open %s didn't open feature_login:\n%s", identifier, r.stdout)
+		}
+	}
+
+	r := e.wt("open", "dosc", "--no-tmux", "--no-editor")
+	if r.err == nil || !strings.Contains(r.stderr, "did you mean 'docs'?") {
+		t.Errorf("open dosc should suggest docs (%v):\n%s", r.err, r.stderr)
+	}
+}
+
+func TestDeleteByBranchName(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feature/login", "--no-tmux", "--no-editor")
+
+	// A fuzzy match is never deleted without confirmation
+	if r := e.wt("delete", "flogin", "--force"); r.err == nil || !strings.Contains(r.stderr, "name it exactly") {
+		t.Errorf("delete --force on a fuzzy match should fail (%v):\n%s", r.err, r.stderr)
+	}
+
+	e.mustWT("delete", "feature/login", "--force")
+	if r := e.mustWT("list"); strings.Contains(r.stdout, "feature_login") {
+		t.Errorf("feature_login still listed:\n%s", r.stdout)
+	}
+}
+
+func TestLockNeedsExactName(t *testing.T) {
+	e := newEnv(t)
+	e.mustWT("new", "feature/login", "--no-tmux", "--no-editor")
+
+	// Without confirmation a fuzzy match is left alone
+	if r := e.wt("lock", "flogin"); r.err == nil || !strings.Contains(r.stderr, "name it exactly") {
+		t.Errorf("lock on a fuzzy match should fail (%v):\n%s", r.err, r.stderr)
+	}
+	if got := e.git(e.repo, "worktree", "list", "--porcelain"); strings.Contains(got, "locked") {
+		t.Errorf("fuzzy-matched worktree was locked:\n%s", got)
+	}
+
+	e.mustWT("lock", "feature_login")
+	if got := e.git(e.repo, "worktree", "list", "--porcelain"); !strings.Contains(got, "locked") {
+		t.Errorf("worktree not locked:\n%s", got)
+	}
+}
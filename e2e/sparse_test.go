@@ -0,0 +1,106 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// monorepo publishes a commit with several services on origin/main and
+// pulls it into the repository.
+func monorepo(e *env) {
+	e.t.Helper()
+	e.git(e.seed, "checkout", "--quiet", "main")
+	for _, f := range []string{"services/api/main.go", "services/web/index.js", "libs/common/util.go"} {
+		e.writeFile(filepath.Join(e.seed, f), f+"\n")
+	}
+	e.git(e.seed, "add", "-A")
+	e.git(e.seed, "commit", "-m", "Add services")
+	e.git(e.seed, "push", "origin", "main")
+	e.git(e.repo, "pull", "--quiet")
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestNewSparse(t *testing.T) {
+	e := newEnv(t)
+	monorepo(e)
+
+	e.mustWT("new", "api", "--sparse", "services/api,libs/common")
+
+	path := e.worktreePath("api")
+	for _, f := range []string{"README.md", "services/api/main.go", "libs/common/util.go"} {
+		if !exists(filepath.Join(path, f)) {
+			t.Errorf("%s not checked out", f)
+		}
+	}
+	if exists(filepath.Join(path, "services/web")) {
+		t.Error("services/web checked out")
+	}
+	if status := e.git(path, "status", "--porcelain"); status != "" {
+		t.Errorf("worktree not clean:\n%s", status)
+	}
+
+	// Editor and tmux open in the first sparse path
+	api := filepath.Join(path, "services", "api")
+	e.assertLogged("tmux", "new-session -d -s app-api -c "+api+" fake-editor "+api)
+	e.assertLogged("editor", api+"|"+api)
+
+	// Sparse checkout is per worktree
+	if !exists(filepath.Join(e.repo, "services/web/index.js")) {
+		t.Error("main worktree became sparse")
+	}
+}
+
+func TestNewSparseFromConfig(t *testing.T) {
+	e := newEnv(t)
+	monorepo(e)
+	e.writeFile(filepath.Join(e.repo, ".wt.toml"), "sparse_paths = [\"services/web\"]\n")
+
+	e.mustWT("new", "web", "--no-tmux", "--no-editor")
+	if path := e.worktreePath("web"); !exists(filepath.Join(path, "services/web/index.js")) || exists(filepath.Join(path, "services/api")) {
+		t.Error("sparse_paths not applied")
+	}
+
+	e.mustWT("new", "full", "--no-sparse", "--no-tmux", "--no-editor")
+	if !exists(filepath.Join(e.worktreePath("full"), "services/api/main.go")) {
+		t.Error("--no-sparse didn't check out everything")
+	}
+}
+
+func TestSparseAddRemove(t *testing.T) {
+	e := newEnv(t)
+	monorepo(e)
+	e.mustWT("new", "api", "--sparse", "services/api", "--no-tmux", "--no-editor")
+	path := e.worktreePath("api")
+
+	e.mustWT("sparse", "add", "--worktree", "api", "libs/common")
+	if !exists(filepath.Join(path, "libs/common/util.go")) {
+		t.Error("libs/common not checked out after add")
+	}
+
+	e.mustWT("sparse", "remove", "-w", "api", "services/api")
+	if exists(filepath.Join(path, "services/api")) {
+		t.Error("services/api still checked out after remove")
+	}
+
+	r := e.mustWT("sparse", "list", "-w", "api")
+	if !strings.Contains(r.stdout, "libs/common") || strings.Contains(r.stdout, "services/api") {
+		t.Errorf("unexpected sparse paths:\n%s", r.stdout)
+	}
+
+	// The last path can't be removed
+	if r := e.wt("sparse", "remove", "-w", "api", "libs/common"); r.err == nil {
+		t.Error("removing the last sparse path succeeded")
+	}
+
+	// Full checkouts aren't sparse
+	e.mustWT("new", "full", "--no-tmux", "--no-editor")
+	if r := e.wt("sparse", "add", "-w", "full", "libs"); r.err == nil || !strings.Contains(r.stderr, "isn't a sparse checkout") {
+		t.Errorf("expected an error for a full checkout, got %v:\n%s", r.err, r.stderr)
+	}
+}
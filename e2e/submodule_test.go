@@ -0,0 +1,81 @@
+package e2e
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// addSubmodule publishes a library repository and commits it to main as the
+// submodule vendor/lib, then initializes it in the main clone.
+func addSubmodule(e *env) {
+	e.t.Helper()
+	// git refuses to clone submodules over the file transport by default
+	e.vars = append(e.vars, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=protocol.file.allow", "GIT_CONFIG_VALUE_0=always")
+
+	lib := filepath.Join(e.root, "lib")
+	e.git(e.root, "init", "-b", "main", lib)
+	e.writeFile(filepath.Join(lib, "lib.go"), "package lib\n")
+	e.git(lib, "add", "lib.go")
+	e.git(lib, "commit", "-m", "Add lib")
+
+	e.git(e.seed, "checkout", "--quiet", "main")
+	e.git(e.seed, "submodule", "add", lib, "vendor/lib")
+	e.git(e.seed, "commit", "-m", "Add lib submodule")
+	e.git(e.seed, "push", "origin", "main")
+
+	e.git(e.repo, "pull", "--quiet")
+	e.git(e.repo, "submodule", "update", "--init")
+}
+
+func TestNewInitializesSubmodulesFromMainRepository(t *testing.T) {
+	e := newEnv(t)
+	addSubmodule(e)
+
+	r := e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+
+	path := e.worktreePath("feat")
+	if !strings.Contains(r.stdout, "Initializing submodules") {
+		t.Errorf("stdout doesn't mention submodules:\n%s", r.stdout)
+	}
+	if _, err := os.Stat(filepath.Join(path, "vendor", "lib", "lib.go")); err != nil {
+		t.Fatalf("submodule not checked out: %v", err)
+	}
+	// Objects were copied from the main repository's module, not borrowed
+	gitDir := e.git(filepath.Join(path, "vendor", "lib"), "rev-parse", "--absolute-git-dir")
+	if _, err := os.Stat(filepath.Join(gitDir, "objects", "info", "alternates")); !os.IsNotExist(err) {
+		t.Errorf("submodule still borrows objects through alternates: %v", err)
+	}
+}
+
+func TestNewSkipsSubmodulesWhenDisabled(t *testing.T) {
+	e := newEnv(t)
+	addSubmodule(e)
+	e.config("init_submodules = false\n")
+
+	e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+
+	if _, err := os.Stat(filepath.Join(e.worktreePath("feat"), "vendor", "lib", "lib.go")); !os.IsNotExist(err) {
+		t.Errorf("submodule checked out despite init_submodules = false: %v", err)
+	}
+}
+
+func TestNewWarnsWhenLFSIsMissing(t *testing.T) {
+	if exec.Command("git", "lfs", "version").Run() == nil {
+		t.Skip("git-lfs is installed")
+	}
+	e := newEnv(t)
+	e.git(e.seed, "checkout", "--quiet", "main")
+	e.writeFile(filepath.Join(e.seed, ".gitattributes"), "*.bin filter=lfs diff=lfs merge=lfs -text\n")
+	e.git(e.seed, "add", ".gitattributes")
+	e.git(e.seed, "commit", "-m", "Track binaries with LFS")
+	e.git(e.seed, "push", "origin", "main")
+
+	r := e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+
+	if !strings.Contains(r.stderr, "git-lfs isn't installed") {
+		t.Errorf("stderr doesn't warn about git-lfs:\n%s", r.stderr)
+	}
+}
@@ -0,0 +1,145 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// trashEntries returns the entry directories in the app project's trash.
+func (e *env) trashEntries() []string {
+	e.t.Helper()
+	entries, err := os.ReadDir(filepath.Join(e.baseDir, ".trash", "app"))
+	if err != nil && !os.IsNotExist(err) {
+		e.t.Fatal(err)
+	}
+	var dirs []string
+	for _, entry := range entries {
+		dirs = append(dirs, filepath.Join(e.baseDir, ".trash", "app", entry.Name()))
+	}
+	return dirs
+}
+
+func TestDeleteRestoreRoundTrip(t *testing.T) {
+	e := newEnv(t)
+	e.config("copy_files = [\".env\"]\n")
+	e.writeFile(filepath.Join(e.repo, ".git", "info", "exclude"), ".env\n")
+	e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+	path := e.worktreePath("feat")
+
+	e.writeFile(filepath.Join(path, "feature.go"), "package app\n")
+	e.git(path, "add", "feature.go")
+	e.git(path, "commit", "-m", "Add feature")
+	tip := e.git(path, "rev-parse", "HEAD")
+	e.writeFile(filepath.Join(path, "README.md"), "app\nwork in progress\n")
+	e.writeFile(filepath.Join(path, "notes.txt"), "notes\n")
+	e.writeFile(filepath.Join(path, ".env"), "SECRET=1\n")
+
+	e.mustWT("delete", "feat", "--force", "--discard-changes", "--delete-branch")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("worktree not deleted: %v", err)
+	}
+	if branches := e.git(e.repo, "branch", "--list", "feat"); branches != "" {
+		t.Fatalf("branch not deleted: %s", branches)
+	}
+
+	r := e.mustWT("trash", "list")
+	if !strings.Contains(r.stdout, "feat") || !strings.Contains(r.stdout, "changes, 2 files") {
+		t.Errorf("trash list doesn't show the saved state:\n%s", r.stdout)
+	}
+
+	e.mustWT("restore", "feat")
+	if got := e.git(path, "rev-parse", "--abbrev-ref", "HEAD"); got != "feat" {
+		t.Errorf("restored worktree is on %q, want feat", got)
+	}
+	if got := e.git(path, "rev-parse", "HEAD"); got != tip {
+		t.Errorf("restored branch is at %s, want the saved tip %s", got, tip)
+	}
+	for file, want := range map[string]string{
+		"README.md": "app\nwork in progress\n",
+		"notes.txt": "notes\n",
+		".env":      "SECRET=1\n",
+	} {
+		if data, err := os.ReadFile(filepath.Join(path, file)); err != nil || string(data) != want {
+			t.Errorf("%s = %q (%v), want %q", file, data, err, want)
+		}
+	}
+
+	if dirs := e.trashEntries(); len(dirs) != 0 {
+		t.Errorf("trash entries left after restore: %q", dirs)
+	}
+	if refs := e.git(e.repo, "for-each-ref", "refs/wt-trash/"); refs != "" {
+		t.Errorf("trash refs left after restore:\n%s", refs)
+	}
+}
+
+func TestExpiredTrashEntriesAreRemoved(t *testing.T) {
+	e := newEnv(t)
+	e.config("trash_retention_days = 7\n")
+	e.mustWT("new", "old", "--no-tmux", "--no-editor")
+	e.mustWT("new", "recent", "--no-tmux", "--no-editor")
+	e.mustWT("delete", "old", "--force")
+	e.mustWT("delete", "recent", "--force")
+
+	dirs := e.trashEntries()
+	if len(dirs) != 2 {
+		t.Fatalf("trash entries = %q, want two", dirs)
+	}
+
+	// Age the entry of old past the retention period
+	var expired string
+	for _, dir := range dirs {
+		metaPath := filepath.Join(dir, "meta.json")
+		data, err := os.ReadFile(metaPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var meta map[string]any
+		if err := json.Unmarshal(data, &meta); err != nil {
+			t.Fatal(err)
+		}
+		if meta["name"] != "old" {
+			continue
+		}
+		expired = meta["id"].(string)
+		meta["deleted_at"] = time.Now().AddDate(0, 0, -8).Format(time.RFC3339)
+		data, _ = json.Marshal(meta)
+		e.writeFile(metaPath, string(data))
+	}
+
+	r := e.mustWT("trash", "list")
+	if strings.Contains(r.stdout, expired) || !strings.Contains(r.stdout, "recent") {
+		t.Errorf("trash list should show only recent:\n%s", r.stdout)
+	}
+	if dirs := e.trashEntries(); len(dirs) != 1 || filepath.Base(dirs[0]) == expired {
+		t.Errorf("trash entries after expiry = %q", dirs)
+	}
+	refs := e.git(e.repo, "for-each-ref", "--format=%(refname)", "refs/wt-trash/")
+	if strings.Contains(refs, expired) || !strings.Contains(refs, "refs/wt-trash/recent-") {
+		t.Errorf("trash refs after expiry:\n%s", refs)
+	}
+}
+
+func TestFailedDeleteLeavesNoTrashEntry(t *testing.T) {
+	e := newEnv(t)
+	addSubmodule(e)
+	e.mustWT("new", "feat", "--no-tmux", "--no-editor")
+
+	// git worktree remove refuses worktrees with submodules unless forced
+	if r := e.wt("delete", "feat", "--force"); r.err == nil {
+		t.Fatalf("delete unexpectedly succeeded:\n%s", r.stdout)
+	}
+	if _, err := os.Stat(e.worktreePath("feat")); err != nil {
+		t.Fatalf("worktree gone: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(e.baseDir, ".trash", "app"))
+	if len(entries) != 0 {
+		t.Errorf("trash entries left behind: %d", len(entries))
+	}
+	if refs := e.git(e.repo, "for-each-ref", "refs/wt-trash/"); refs != "" {
+		t.Errorf("trash refs left behind:\n%s", refs)
+	}
+}
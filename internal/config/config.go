@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -17,12 +19,50 @@ type Config struct {
 	WorktreesLocation string       `toml:"worktrees_location"`
 	CopyFiles         []string     `toml:"copy_files"`
 	TmuxWindows       []TmuxWindow `toml:"tmux_windows"`
+	SyncStrategy      string       `toml:"sync_strategy"`
+	BaseBranch        string       `toml:"base_branch"`
+	// Pointers distinguish "unset" from false so local config can override global
+	DeleteBranch       *bool    `toml:"delete_branch"`
+	DeleteRemoteBranch *bool    `toml:"delete_remote_branch"`
+	TrashRetentionDays *int     `toml:"trash_retention_days"`
+	Protected          []string `toml:"protected"`
+	FetchBase          *bool    `toml:"fetch_base"`
+	// Durations such as "10m" or "90s"
+	FetchTTL     string `toml:"fetch_ttl"`
+	FetchTimeout string `toml:"fetch_timeout"`
+	PRRemote     string `toml:"pr_remote"`
+	// Ref pull requests are published under, e.g. "refs/pull/{number}/head"
+	PRRefspec string `toml:"pr_refspec"`
+	// Directories new worktrees check out with cone-mode sparse checkout
+	SparsePaths []string `toml:"sparse_paths"`
+	// Set up submodules and Git LFS files in new worktrees
+	InitSubmodules *bool `toml:"init_submodules"`
+	PullLFS        *bool `toml:"pull_lfs"`
+	// Names of branches created by `wt new`, e.g. "{user}/{ticket}-{slug}"
+	BranchTemplate string `toml:"branch_template"`
+	BranchPattern  string `toml:"branch_pattern"`
+	BranchUser     string `toml:"branch_user"`
+	// How worktree directories are named: "flat", "nested" or "hashed"
+	WorktreeNaming string `toml:"worktree_naming"`
+	// What repositories are filed under: "name", "remote" or "path"
+	RepoLayout string `toml:"repo_layout"`
 }
 
+const (
+	// Default number of days deleted worktrees are kept in the trash
+	defaultTrashRetentionDays = 7
+	// Default time after which a fetch is abandoned in favor of local refs
+	defaultFetchTimeout = time.Minute
+)
+
 var defaultConfig = Config{
 	WorktreesLocation: filepath.Join(os.Getenv("HOME"), "projects", "worktrees"),
 	CopyFiles:         []string{},
 	TmuxWindows:       []TmuxWindow{},
+	SyncStrategy:      "rebase",
+	Protected:         []string{},
+	WorktreeNaming:    "flat",
+	RepoLayout:        "name",
 }
 
 var currentConfig *Config
@@ -44,6 +84,61 @@ func Load() (*Config, error) {
 		}
 		config.CopyFiles = append(config.CopyFiles, globalConfig.CopyFiles...)
 		config.TmuxWindows = append(config.TmuxWindows, globalConfig.TmuxWindows...)
+		if globalConfig.SyncStrategy != "" {
+			config.SyncStrategy = globalConfig.SyncStrategy
+		}
+		if globalConfig.BaseBranch != "" {
+			config.BaseBranch = globalConfig.BaseBranch
+		}
+		if globalConfig.FetchBase != nil {
+			config.FetchBase = globalConfig.FetchBase
+		}
+		if globalConfig.FetchTTL != "" {
+			config.FetchTTL = globalConfig.FetchTTL
+		}
+		if globalConfig.FetchTimeout != "" {
+			config.FetchTimeout = globalConfig.FetchTimeout
+		}
+		if globalConfig.PRRemote != "" {
+			config.PRRemote = globalConfig.PRRemote
+		}
+		if globalConfig.PRRefspec != "" {
+			config.PRRefspec = globalConfig.PRRefspec
+		}
+		if len(globalConfig.SparsePaths) > 0 {
+			config.SparsePaths = globalConfig.SparsePaths
+		}
+		if globalConfig.InitSubmodules != nil {
+			config.InitSubmodules = globalConfig.InitSubmodules
+		}
+		if globalConfig.PullLFS != nil {
+			config.PullLFS = globalConfig.PullLFS
+		}
+		if globalConfig.BranchTemplate != "" {
+			config.BranchTemplate = globalConfig.BranchTemplate
+		}
+		if globalConfig.BranchPattern != "" {
+			config.BranchPattern = globalConfig.BranchPattern
+		}
+		if globalConfig.BranchUser != "" {
+			config.BranchUser = globalConfig.BranchUser
+		}
+		if globalConfig.WorktreeNaming != "" {
+			config.WorktreeNaming = globalConfig.WorktreeNaming
+		}
+		if globalConfig.RepoLayout != "" {
+			config.RepoLayout = globalConfig.RepoLayout
+		}
+		if globalConfig.DeleteBranch != nil {
+			config.DeleteBranch = globalConfig.DeleteBranch
+		}
+		if globalConfig.DeleteRemoteBranch != nil {
+			config.DeleteRemoteBranch = globalConfig.DeleteRemoteBranch
+		}
+		if globalConfig.TrashRetentionDays != nil {
+			config.TrashRetentionDays = globalConfig.TrashRetentionDays
+		}
+		config.Protected = append(config.Protected, globalConfig.Protected...)
 	} else if !os.IsNotExist(err) {
 		return nil, fmt.Errorf("error loading global config: %w", err)
 	}
@@ -60,12 +155,69 @@ func Load() (*Config, error) {
 		config.CopyFiles = append(config.CopyFiles, localConfig.CopyFiles...)
 		// Merge tmux_windows arrays (local adds to global)
 		config.TmuxWindows = append(config.TmuxWindows, localConfig.TmuxWindows...)
+		if localConfig.SyncStrategy != "" {
+			config.SyncStrategy = localConfig.SyncStrategy
+		}
+		if localConfig.BaseBranch != "" {
+			config.BaseBranch = localConfig.BaseBranch
+		}
+		if localConfig.FetchBase != nil {
+			config.FetchBase = localConfig.FetchBase
+		}
+		if localConfig.FetchTTL != "" {
+			config.FetchTTL = localConfig.FetchTTL
+		}
+		if localConfig.FetchTimeout != "" {
+			config.FetchTimeout = localConfig.FetchTimeout
+		}
+		if localConfig.PRRemote != "" {
+			config.PRRemote = localConfig.PRRemote
+		}
+		if localConfig.PRRefspec != "" {
+			config.PRRefspec = localConfig.PRRefspec
+		}
+		if len(localConfig.SparsePaths) > 0 {
+			config.SparsePaths = localConfig.SparsePaths
+		}
+		if localConfig.InitSubmodules != nil {
+			config.InitSubmodules = localConfig.InitSubmodules
+		}
+		if localConfig.PullLFS != nil {
+			config.PullLFS = localConfig.PullLFS
+		}
+		if localConfig.BranchTemplate != "" {
+			config.BranchTemplate = localConfig.BranchTemplate
+		}
+		if localConfig.BranchPattern != "" {
+			config.BranchPattern = localConfig.BranchPattern
+		}
+		if localConfig.BranchUser != "" {
+			config.BranchUser = localConfig.BranchUser
+		}
+		if localConfig.WorktreeNaming != "" {
+			config.WorktreeNaming = localConfig.WorktreeNaming
+		}
+		if localConfig.RepoLayout != "" {
+			config.RepoLayout = localConfig.RepoLayout
+		}
+		if localConfig.DeleteBranch != nil {
+			config.DeleteBranch = localConfig.DeleteBranch
+		}
+		if localConfig.DeleteRemoteBranch != nil {
+			config.DeleteRemoteBranch = localConfig.DeleteRemoteBranch
+		}
+		if localConfig.TrashRetentionDays != nil {
+			config.TrashRetentionDays = localConfig.TrashRetentionDays
+		}
+		// Merge protected patterns (local adds to global)
+		config.Protected = append(config.Protected, localConfig.Protected...)
 	} else if !os.IsNotExist(err) {
 		return nil, fmt.Errorf("error loading local config: %w", err)
 	}
 
-	// Remove duplicates from CopyFiles
+	// Remove duplicates from CopyFiles and Protected
 	config.CopyFiles = removeDuplicates(config.CopyFiles)
+	config.Protected = removeDuplicates(config.Protected)
 
 	config.WorktreesLocation = expandPath(config.WorktreesLocation)
 
@@ -152,6 +304,224 @@ func GetTmuxWindows() []TmuxWindow {
 	return config.TmuxWindows
 }
 
+func GetSyncStrategy() string {
+	config, err := Load()
+	if err != nil {
+		return defaultConfig.SyncStrategy
+	}
+	return config.SyncStrategy
+}
+
+// GetBaseBranch returns the configured ref new branches start from, or ""
+// to use the remote's default branch.
+func GetBaseBranch() string {
+	config, err := Load()
+	if err != nil {
+		return ""
+	}
+	return config.BaseBranch
+}
+
+// GetFetchBase reports whether the base ref is fetched before branching from
+// it. Defaults to true.
+func GetFetchBase() bool {
+	config, err := Load()
+	if err != nil || config.FetchBase == nil {
+		return true
+	}
+	return *config.FetchBase
+}
+
+// GetFetchTTL returns how recent the last fetch may be for wt to skip
+// fetching again. Zero (the default) always fetches.
+func GetFetchTTL() time.Duration {
+	config, err := Load()
+	if err != nil || config.FetchTTL == "" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(config.FetchTTL)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// GetFetchTimeout returns how long a fetch may run before wt gives up and
+// uses local refs. Zero disables the timeout.
+func GetFetchTimeout() time.Duration {
+	config, err := Load()
+	if err != nil || config.FetchTimeout == "" {
+		return defaultFetchTimeout
+	}
+	timeout, err := time.ParseDuration(config.FetchTimeout)
+	if err != nil || timeout < 0 {
+		return defaultFetchTimeout
+	}
+	return timeout
+}
+
+// GetPRRemote returns the remote `wt new --pr` fetches pull requests from,
+// or "" to pick one automatically.
+func GetPRRemote() string {
+	config, err := Load()
+	if err != nil {
+		return ""
+	}
+	return config.PRRemote
+}
+
+// GetPRRefspec returns the ref pull requests are fetched from, with
+// {number} standing for the pull request number, or "" to guess it from the
+// remote's URL.
+func GetPRRefspec() string {
+	config, err := Load()
+	if err != nil {
+		return ""
+	}
+	return config.PRRefspec
+}
+
+// GetSparsePaths returns the directories new worktrees check out, or nil
+// for a full checkout. Local config replaces rather than extends the global
+// list, since the paths are specific to a repository.
+func GetSparsePaths() []string {
+	config, err := Load()
+	if err != nil {
+		return nil
+	}
+	return config.SparsePaths
+}
+
+// GetInitSubmodules reports whether new worktrees get their submodules
+// initialized. Defaults to true.
+func GetInitSubmodules() bool {
+	config, err := Load()
+	if err != nil || config.InitSubmodules == nil {
+		return true
+	}
+	return *config.InitSubmodules
+}
+
+// GetPullLFS reports whether Git LFS files are pulled into new worktrees.
+// Defaults to true.
+func GetPullLFS() bool {
+	config, err := Load()
+	if err != nil || config.PullLFS == nil {
+		return true
+	}
+	return *config.PullLFS
+}
+
+// GetBranchTemplate returns the template names of new branches are built
+// from, or "" to use the name as given.
+func GetBranchTemplate() string {
+	config, err := Load()
+	if err != nil {
+		return ""
+	}
+	return config.BranchTemplate
+}
+
+// GetBranchPattern returns the regular expression new branch names must
+// match, or "" for no restriction.
+func GetBranchPattern() string {
+	config, err := Load()
+	if err != nil {
+		return ""
+	}
+	return config.BranchPattern
+}
+
+// GetBranchUser returns the {user} value of branch templates, or "" to use
+// the login name.
+func GetBranchUser() string {
+	config, err := Load()
+	if err != nil {
+		return ""
+	}
+	return config.BranchUser
+}
+
+// GetWorktreeNaming returns how new worktree directories are named:
+// "flat", "nested" or "hashed".
+func GetWorktreeNaming() string {
+	config, err := Load()
+	if err != nil {
+		return defaultConfig.WorktreeNaming
+	}
+	return config.WorktreeNaming
+}
+
+// GetRepoLayout returns what a repository's worktree directory is named
+// after: "name", "remote" or "path".
+func GetRepoLayout() string {
+	config, err := Load()
+	if err != nil {
+		return defaultConfig.RepoLayout
+	}
+	return config.RepoLayout
+}
+
+func GetDeleteBranch() bool {
+	config, err := Load()
+	if err != nil || config.DeleteBranch == nil {
+		return false
+	}
+	return *config.DeleteBranch
+}
+
+func GetDeleteRemoteBranch() bool {
+	config, err := Load()
+	if err != nil || config.DeleteRemoteBranch == nil {
+		return false
+	}
+	return *config.DeleteRemoteBranch
+}
+
+// GetTrashRetentionDays returns how many days deleted worktrees are kept in
+// the trash. Zero disables the trash.
+func GetTrashRetentionDays() int {
+	config, err := Load()
+	if err != nil || config.TrashRetentionDays == nil {
+		return defaultTrashRetentionDays
+	}
+	if *config.TrashRetentionDays < 0 {
+		return 0
+	}
+	return *config.TrashRetentionDays
+}
+
+// GetProtected returns the protected patterns. A config that fails to load
+// is an error rather than an empty list, so protection fails closed.
+func GetProtected() ([]string, error) {
+	config, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return config.Protected, nil
+}
+
+// MatchProtected returns the first protected glob matching any of the given
+// worktree or branch names. It fails when the patterns can't be loaded;
+// callers must then treat the worktree as protected.
+func MatchProtected(names ...string) (string, bool, error) {
+	patterns, err := GetProtected()
+	if err != nil {
+		return "", false, fmt.Errorf("can't check protected patterns: %w", err)
+	}
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			if matched, err := path.Match(pattern, name); err == nil && matched {
+				return pattern, true, nil
+			}
+		}
+	}
+	return "", false, nil
+}
+
 func CreateGlobalConfigDir() error {
 	configPath := getGlobalConfigPath()
 	configDir := filepath.Dir(configPath)
@@ -2,14 +2,14 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
-	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
 )
 
 func CreateBranch(branchName string) error {
-	cmd := exec.Command("git", "branch", branchName)
-	output, err := cmd.CombinedOutput()
+	output, err := run.CombinedOutput(runner.Command("git", "branch", branchName))
 	if err != nil {
 		return fmt.Errorf("failed to create branch %s: %s", branchName, string(output))
 	}
@@ -17,8 +17,7 @@ func CreateBranch(branchName string) error {
 }
 
 func GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	output, err := cmd.Output()
+	output, err := run.Output(runner.Command("git", "rev-parse", "--abbrev-ref", "HEAD"))
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
@@ -26,85 +25,150 @@ func GetCurrentBranch() (string, error) {
 }
 
 func CheckoutBranch(branchName string) error {
-	cmd := exec.Command("git", "checkout", branchName)
-	output, err := cmd.CombinedOutput()
+	output, err := run.CombinedOutput(runner.Command("git", "checkout", branchName))
 	if err != nil {
 		return fmt.Errorf("failed to checkout branch %s: %s", branchName, string(output))
 	}
 	return nil
 }
 
+// RemoteBranch is a remote's copy of a branch.
+type RemoteBranch struct {
+	Remote string
+	SHA    string
+}
+
 type Branch struct {
 	Name     string
 	IsRemote bool
 	IsLocal  bool
+	// SHA is the tip of the local branch; empty for remote-only branches
+	SHA string
+	// Remotes that have a branch with this name, sorted by remote name
+	Remotes []RemoteBranch
 }
 
-func ListAllBranches() ([]Branch, error) {
-	branches := make(map[string]*Branch)
+// Remote returns the copy of the branch on the named remote.
+func (b Branch) Remote(name string) (RemoteBranch, bool) {
+	for _, r := range b.Remotes {
+		if r.Remote == name {
+			return r, true
+		}
+	}
+	return RemoteBranch{}, false
+}
 
-	// Get local branches
-	localCmd := exec.Command("git", "branch", "--format=%(refname:short)")
-	localOutput, err := localCmd.Output()
+// ListAllBranches returns local and remote-tracking branches sorted by name.
+// Remote branches are matched to local ones by name, with each remote that
+// has the branch listed separately so origin/foo and upstream/foo stay
+// distinguishable.
+func ListAllBranches() ([]Branch, error) {
+	remotes, err := ListRemotes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list local branches: %w", err)
+		return nil, err
 	}
 
-	for _, line := range strings.Split(string(localOutput), "\n") {
-		name := strings.TrimSpace(line)
-		if name != "" {
-			branches[name] = &Branch{
-				Name:    name,
-				IsLocal: true,
-			}
-		}
+	output, err := run.Output(runner.Command("git", "for-each-ref", "--format=%(refname)%00%(objectname)", "refs/heads", "refs/remotes"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
 	}
 
-	// Get remote branches
-	remoteCmd := exec.Command("git", "branch", "-r", "--format=%(refname:short)")
-	remoteOutput, err := remoteCmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list remote branches: %w", err)
+	return parseBranchRefs(string(output), remotes), nil
+}
+
+// parseBranchRefs parses `git for-each-ref --format=%(refname)%00%(objectname)`
+// output for refs/heads and refs/remotes.
+func parseBranchRefs(output string, remotes []string) []Branch {
+	branches := make(map[string]*Branch)
+	get := func(name string) *Branch {
+		if b, ok := branches[name]; ok {
+			return b
+		}
+		b := &Branch{Name: name}
+		branches[name] = b
+		return b
 	}
 
-	// Pattern to extract branch name from remote refs (e.g., origin/main -> main)
-	remotePattern := regexp.MustCompile(`^[^/]+/(.+)$`)
+	for _, line := range strings.Split(output, "\n") {
+		ref, sha, found := strings.Cut(strings.TrimSpace(line), "\x00")
+		if !found {
+			continue
+		}
 
-	for _, line := range strings.Split(string(remoteOutput), "\n") {
-		remoteName := strings.TrimSpace(line)
-		if remoteName == "" || strings.HasSuffix(remoteName, "/HEAD") {
+		if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			b := get(name)
+			b.IsLocal = true
+			b.SHA = sha
 			continue
 		}
 
-		// Extract the branch name without the remote prefix
-		matches := remotePattern.FindStringSubmatch(remoteName)
-		if len(matches) > 1 {
-			branchName := matches[1]
-			if existing, ok := branches[branchName]; ok {
-				existing.IsRemote = true
-			} else {
-				branches[branchName] = &Branch{
-					Name:     branchName,
-					IsRemote: true,
-				}
-			}
+		remote, name, ok := splitRemoteRef(remotes, ref)
+		// refs/remotes/<remote>/HEAD is a pointer to the remote's default branch
+		if !ok || name == "HEAD" {
+			continue
 		}
+		b := get(name)
+		b.IsRemote = true
+		b.Remotes = append(b.Remotes, RemoteBranch{Remote: remote, SHA: sha})
 	}
 
-	// Convert map to slice
 	result := make([]Branch, 0, len(branches))
-	for _, branch := range branches {
-		result = append(result, *branch)
+	for _, b := range branches {
+		sort.Slice(b.Remotes, func(i, j int) bool {
+			return b.Remotes[i].Remote < b.Remotes[j].Remote
+		})
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// FindBranch looks up spec, which is either a branch name or a remote branch
+// such as upstream/foo. remote is set when spec names a remote explicitly.
+// It returns a nil branch when nothing matches.
+func FindBranch(spec string) (branch *Branch, remote string, err error) {
+	branches, err := ListAllBranches()
+	if err != nil {
+		return nil, "", err
+	}
+
+	byName := make(map[string]*Branch)
+	for i := range branches {
+		byName[branches[i].Name] = &branches[i]
+	}
+
+	// A local branch wins, even if its name looks like <remote>/<branch>
+	if b, ok := byName[spec]; ok && b.IsLocal {
+		return b, "", nil
+	}
+
+	remotes, err := ListRemotes()
+	if err != nil {
+		return nil, "", err
+	}
+	if r, name, ok := splitRemoteRef(remotes, spec); ok {
+		if b, ok := byName[name]; ok {
+			if _, onRemote := b.Remote(r); onRemote {
+				return b, r, nil
+			}
+		}
 	}
 
-	return result, nil
+	if b, ok := byName[spec]; ok {
+		return b, "", nil
+	}
+	return nil, "", nil
 }
 
-func FetchRemoteBranches() error {
-	cmd := exec.Command("git", "fetch", "--all")
-	output, err := cmd.CombinedOutput()
+// CreateTrackingBranch creates a local branch from remote's copy of it, with
+// its upstream set to that remote.
+func CreateTrackingBranch(branchName, remote string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "branch", "--track", branchName, "refs/remotes/"+remote+"/"+branchName))
 	if err != nil {
-		return fmt.Errorf("failed to fetch remote branches: %s", string(output))
+		return fmt.Errorf("failed to create branch %s tracking %s/%s: %s", branchName, remote, branchName, string(output))
 	}
 	return nil
 }
@@ -122,3 +186,131 @@ func BranchExists(branchName string) (bool, error) {
 	}
 	return false, nil
 }
+
+func DeleteBranch(branchName string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	output, err := run.CombinedOutput(runner.Command("git", "branch", flag, branchName))
+	if err != nil {
+		return fmt.Errorf("failed to delete branch %s: %s", branchName, string(output))
+	}
+	return nil
+}
+
+// GetBranchUpstream returns the remote name and the branch name on that
+// remote that branchName tracks. Both are empty when no upstream is set.
+func GetBranchUpstream(branchName string) (string, string, error) {
+	output, err := run.Output(runner.Command("git", "for-each-ref", "--format=%(upstream:remotename) %(upstream:remoteref)", "refs/heads/"+branchName))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get upstream of %s: %w", branchName, err)
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return "", "", nil
+	}
+	return fields[0], strings.TrimPrefix(fields[1], "refs/heads/"), nil
+}
+
+func DeleteRemoteBranch(remote, branchName string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "push", remote, "--delete", branchName))
+	if err != nil {
+		return fmt.Errorf("failed to delete remote branch %s/%s: %s", remote, branchName, string(output))
+	}
+	return nil
+}
+
+// RenameBranch renames a local branch, including when it is checked out in a
+// worktree.
+func RenameBranch(oldName, newName string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "branch", "-m", oldName, newName))
+	if err != nil {
+		return fmt.Errorf("failed to rename branch %s to %s: %s", oldName, newName, string(output))
+	}
+	return nil
+}
+
+// CreateBranchAt creates branchName pointing at startPoint. The new branch
+// never tracks startPoint, even when it is a remote branch such as
+// origin/main, so pushing it doesn't target the base branch.
+func CreateBranchAt(branchName, startPoint string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "branch", "--no-track", branchName, startPoint))
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s at %s: %s", branchName, startPoint, string(output))
+	}
+	return nil
+}
+
+// CheckBranchName reports whether name is allowed as a branch name by git's
+// ref-format rules, so bad names are caught before anything is created.
+func CheckBranchName(name string) error {
+	if err := run.Run(runner.Command("git", "check-ref-format", "--branch", name)); err != nil {
+		return fmt.Errorf("'%s' isn't a valid branch name (no spaces, '..', '~', '^', ':', '?', '*', '[', '\\', leading '-' or trailing '.lock' or '/')", name)
+	}
+	return nil
+}
+
+func LocalBranchExists(branchName string) bool {
+	return run.Run(runner.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branchName)) == nil
+}
+
+// UpdateRef points ref at commit in the repository containing repoPath,
+// keeping the commit reachable (and safe from garbage collection) even if no
+// branch references it.
+func UpdateRef(repoPath, ref, commit string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "-C", repoPath, "update-ref", ref, commit))
+	if err != nil {
+		return fmt.Errorf("failed to update ref %s: %s", ref, string(output))
+	}
+	return nil
+}
+
+func DeleteRef(repoPath, ref string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "-C", repoPath, "update-ref", "-d", ref))
+	if err != nil {
+		return fmt.Errorf("failed to delete ref %s: %s", ref, string(output))
+	}
+	return nil
+}
+
+// ResolveCommit returns the full commit SHA that ref (a branch, tag, SHA or
+// any other revision) points to.
+func ResolveCommit(ref string) (string, error) {
+	output, err := run.Output(runner.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}"))
+	if err != nil {
+		return "", fmt.Errorf("unknown revision '%s'", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListRemotes returns the names of the repository's remotes.
+func ListRemotes() ([]string, error) {
+	output, err := run.Output(runner.Command("git", "remote"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	return nonEmptyLines(string(output)), nil
+}
+
+// SplitRemoteRef splits a remote branch name such as origin/main into its
+// remote and branch. ok is false when ref doesn't start with a known remote.
+func SplitRemoteRef(ref string) (remote, branchName string, ok bool) {
+	remotes, err := ListRemotes()
+	if err != nil {
+		return "", "", false
+	}
+	return splitRemoteRef(remotes, ref)
+}
+
+func splitRemoteRef(remotes []string, ref string) (remote, branchName string, ok bool) {
+	ref = strings.TrimPrefix(ref, "refs/remotes/")
+	for _, r := range remotes {
+		// Prefer the longest match in case remote names contain slashes
+		if strings.HasPrefix(ref, r+"/") && len(ref) > len(r)+1 && len(r) > len(remote) {
+			remote, branchName, ok = r, ref[len(r)+1:], true
+		}
+	}
+	return remote, branchName, ok
+}
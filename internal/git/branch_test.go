@@ -0,0 +1,119 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// useFake installs a fake runner for the duration of the test.
+func useFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	fake := runner.NewFake()
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+	return fake
+}
+
+func refLines(refs ...string) string {
+	var b strings.Builder
+	for i := 0; i < len(refs); i += 2 {
+		b.WriteString(refs[i] + "\x00" + refs[i+1] + "\n")
+	}
+	return b.String()
+}
+
+func TestParseBranchRefs(t *testing.T) {
+	output := refLines(
+		"refs/heads/main", "aaa",
+		"refs/heads/feature/login", "bbb",
+		"refs/remotes/origin/HEAD", "ccc",
+		"refs/remotes/origin/main", "ccc",
+		"refs/remotes/upstream/main", "ddd",
+		"refs/remotes/upstream/fix", "eee",
+		"refs/remotes/team/a/wip", "fff",
+	)
+
+	got := parseBranchRefs(output, []string{"origin", "upstream", "team/a"})
+	want := []Branch{
+		{Name: "feature/login", IsLocal: true, SHA: "bbb"},
+		{Name: "fix", IsRemote: true, Remotes: []RemoteBranch{{Remote: "upstream", SHA: "eee"}}},
+		{Name: "main", IsLocal: true, IsRemote: true, SHA: "aaa", Remotes: []RemoteBranch{
+			{Remote: "origin", SHA: "ccc"},
+			{Remote: "upstream", SHA: "ddd"},
+		}},
+		{Name: "wip", IsRemote: true, Remotes: []RemoteBranch{{Remote: "team/a", SHA: "fff"}}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBranchRefs() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSplitRemoteRefPrefersLongestRemote(t *testing.T) {
+	remotes := []string{"team", "team/a"}
+
+	tests := []struct {
+		ref, remote, branch string
+		ok                  bool
+	}{
+		{"team/a/wip", "team/a", "wip", true},
+		{"team/main", "team", "main", true},
+		{"refs/remotes/team/main", "team", "main", true},
+		{"origin/main", "", "", false},
+		{"team/", "", "", false},
+	}
+
+	for _, tt := range tests {
+		remote, branch, ok := splitRemoteRef(remotes, tt.ref)
+		if remote != tt.remote || branch != tt.branch || ok != tt.ok {
+			t.Errorf("splitRemoteRef(%q) = %q, %q, %v; want %q, %q, %v", tt.ref, remote, branch, ok, tt.remote, tt.branch, tt.ok)
+		}
+	}
+}
+
+func TestFindBranch(t *testing.T) {
+	fake := useFake(t)
+	fake.OnOutput("git remote", "origin\nupstream\n")
+	fake.OnOutput("git for-each-ref", refLines(
+		"refs/heads/main", "aaa",
+		"refs/remotes/origin/foo", "bbb",
+		"refs/remotes/upstream/foo", "ccc",
+	))
+
+	tests := []struct {
+		spec, name, remote string
+	}{
+		{"main", "main", ""},
+		{"foo", "foo", ""},
+		{"upstream/foo", "foo", "upstream"},
+		{"origin/foo", "foo", "origin"},
+	}
+	for _, tt := range tests {
+		branch, remote, err := FindBranch(tt.spec)
+		if err != nil {
+			t.Fatalf("FindBranch(%q): %v", tt.spec, err)
+		}
+		if branch == nil || branch.Name != tt.name || remote != tt.remote {
+			t.Errorf("FindBranch(%q) = %+v, %q; want %q, %q", tt.spec, branch, remote, tt.name, tt.remote)
+		}
+	}
+
+	if branch, _, err := FindBranch("upstream/missing"); err != nil || branch != nil {
+		t.Errorf("FindBranch(upstream/missing) = %+v, %v; want nil", branch, err)
+	}
+}
+
+func TestCreateTrackingBranchUsesFullRemoteRef(t *testing.T) {
+	fake := useFake(t)
+	fake.OnOutput("git branch --track", "")
+
+	if err := CreateTrackingBranch("foo", "upstream"); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.Ran("git branch --track foo refs/remotes/upstream/foo") {
+		t.Errorf("calls = %q", fake.Calls())
+	}
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// ErrFetchTimeout is returned by Fetch when the fetch didn't finish in time.
+var ErrFetchTimeout = errors.New("fetch timed out")
+
+// FetchOptions describes what Fetch fetches and how.
+type FetchOptions struct {
+	// Remote to fetch from; empty fetches all remotes
+	Remote string
+	// Branch limits the fetch to a single branch of Remote
+	Branch string
+	// Refspec fetches this refspec from Remote instead of Branch
+	Refspec string
+	// Timeout aborts the fetch after this long; zero waits indefinitely
+	Timeout time.Duration
+	// Progress receives git's progress output; nil hides it
+	Progress io.Writer
+}
+
+// Fetch runs git fetch as described by opts.
+func Fetch(opts FetchOptions) error {
+	args := []string{"fetch"}
+	if opts.Progress != nil {
+		args = append(args, "--progress")
+	}
+	if opts.Remote == "" {
+		args = append(args, "--all")
+	} else {
+		args = append(args, opts.Remote)
+		if opts.Refspec != "" {
+			args = append(args, opts.Refspec)
+		} else if opts.Branch != "" {
+			args = append(args, opts.Branch)
+		}
+	}
+
+	cmd := runner.Command("git", args...)
+	// Don't let a credential or host key prompt hang the fetch
+	cmd.Env = []string{"GIT_TERMINAL_PROMPT=0"}
+	cmd.Timeout = opts.Timeout
+
+	var output strings.Builder
+	if opts.Progress != nil {
+		cmd.Stderr = io.MultiWriter(opts.Progress, &output)
+	} else {
+		cmd.Stderr = &output
+	}
+
+	err := run.Run(cmd)
+	if errors.Is(err, runner.ErrTimeout) {
+		return fmt.Errorf("%w after %s", ErrFetchTimeout, opts.Timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %s", fetchTarget(opts), strings.TrimSpace(lastLine(output.String())))
+	}
+	return nil
+}
+
+func fetchTarget(opts FetchOptions) string {
+	switch {
+	case opts.Remote == "":
+		return "remotes"
+	case opts.Refspec != "":
+		source, _, _ := strings.Cut(strings.TrimPrefix(opts.Refspec, "+"), ":")
+		return opts.Remote + " " + source
+	case opts.Branch != "":
+		return opts.Remote + "/" + opts.Branch
+	default:
+		return opts.Remote
+	}
+}
+
+// lastLine returns the last non-empty line of git's stderr, which holds the
+// error once progress output is mixed in.
+func lastLine(s string) string {
+	lines := nonEmptyLines(strings.ReplaceAll(s, "\r", "\n"))
+	if len(lines) == 0 {
+		return s
+	}
+	return lines[len(lines)-1]
+}
+
+// LastFetchTime returns when the repository was last fetched, based on the
+// modification time of FETCH_HEAD. ok is false if it was never fetched.
+func LastFetchTime() (time.Time, bool) {
+	var candidates []string
+
+	// FETCH_HEAD is written to the git dir of the worktree that fetched, so
+	// check the current worktree's as well as the main repository's
+	if output, err := run.Output(runner.Command("git", "rev-parse", "--path-format=absolute", "--git-path", "FETCH_HEAD")); err == nil {
+		candidates = append(candidates, strings.TrimSpace(string(output)))
+	}
+	if commonDir, err := GetGitCommonDir(); err == nil {
+		candidates = append(candidates, filepath.Join(commonDir, "FETCH_HEAD"))
+	}
+
+	var latest time.Time
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, !latest.IsZero()
+}
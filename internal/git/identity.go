@@ -0,0 +1,392 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// Values of the repo_layout config key, deciding what a repository's
+// worktree directory is named after.
+const (
+	// LayoutName uses the main repository directory's name, e.g. api
+	LayoutName = "name"
+	// LayoutRemote uses the remote's host and path, e.g. github.com/acme/api
+	LayoutRemote = "remote"
+	// LayoutPath uses the main repository's path relative to the home
+	// directory, e.g. work/api
+	LayoutPath = "path"
+)
+
+// RepositoryIdentity returns the name the current repository's worktrees are
+// filed under with layout. Repositories without a remote fall back to the
+// path layout.
+func RepositoryIdentity(layout string) (string, error) {
+	mainPath, err := MainRepositoryPath()
+	if err != nil {
+		return "", err
+	}
+
+	var identity string
+	switch layout {
+	case LayoutName:
+		identity = filepath.Base(mainPath)
+	case LayoutRemote:
+		if identity = remoteIdentity(); identity == "" {
+			identity = pathIdentity(mainPath)
+		}
+	case LayoutPath:
+		identity = pathIdentity(mainPath)
+	default:
+		return "", fmt.Errorf("unknown repo_layout '%s' (expected 'name', 'remote' or 'path')", layout)
+	}
+
+	if identity == "" {
+		return "", fmt.Errorf("unable to determine repository name")
+	}
+	return identity, nil
+}
+
+// MainRepositoryPath returns the main worktree of the current repository,
+// the directory holding its .git directory.
+func MainRepositoryPath() (string, error) {
+	gitCommonDir, err := GetGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+
+	// If the common dir ends with .git, we're in a worktree or the main repo
+	if filepath.Base(gitCommonDir) != ".git" {
+		return "", fmt.Errorf("unable to determine repository name")
+	}
+	return filepath.Dir(gitCommonDir), nil
+}
+
+// remoteIdentity returns the identity of origin's URL, or of the first
+// remote when there's no origin, or "" for repositories without one.
+func remoteIdentity() string {
+	remotes, err := ListRemotes()
+	if err != nil || len(remotes) == 0 {
+		return ""
+	}
+	remote := remotes[0]
+	for _, r := range remotes {
+		if r == "origin" {
+			remote = r
+		}
+	}
+
+	remoteURL, err := GetRemoteURL(remote)
+	if err != nil {
+		return ""
+	}
+	return parseRemoteIdentity(remoteURL)
+}
+
+// parseRemoteIdentity turns a remote URL such as git@github.com:acme/api.git
+// or https://github.com/acme/api into github.com/acme/api. Local paths and
+// file:// URLs have no host and give "".
+func parseRemoteIdentity(remoteURL string) string {
+	var host, path string
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil || u.Scheme == "file" {
+			return ""
+		}
+		host, path = u.Hostname(), u.Path
+	} else if i := strings.Index(remoteURL, ":"); i > 0 && !strings.Contains(remoteURL[:i], "/") {
+		// scp-like syntax: [user@]host:path
+		host, path = remoteURL[:i], remoteURL[i+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	} else {
+		return ""
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return ""
+	}
+	return sanitizeIdentity(strings.ToLower(host + "/" + path))
+}
+
+// pathIdentity returns mainPath relative to the home directory, or without
+// its leading separator when it's elsewhere.
+func pathIdentity(mainPath string) string {
+	path := filepath.Clean(mainPath)
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return sanitizeIdentity(filepath.ToSlash(path))
+}
+
+// sanitizeIdentity makes each "/"-separated segment of identity safe as a
+// directory name. Segments can't start with "." since hidden directories of
+// the worktree base directory hold wt's own state.
+func sanitizeIdentity(identity string) string {
+	var segments []string
+	for _, segment := range strings.Split(identity, "/") {
+		segment = strings.TrimLeft(SanitizeBranchName(segment), ".")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// ProjectRecord ties a project, the name worktrees are filed under, to the
+// repository they belong to, so two repositories never share a worktree
+// directory unnoticed.
+type ProjectRecord struct {
+	Name       string `json:"name"`
+	Repository string `json:"repository"`
+}
+
+// ProjectCollisionError reports a project name already used by another
+// repository.
+type ProjectCollisionError struct {
+	Name       string
+	Repository string
+}
+
+func (e *ProjectCollisionError) Error() string {
+	return fmt.Sprintf("worktree directory %s already belongs to the repository at %s; set repo_layout = \"remote\" or \"path\" and run 'wt migrate' in both repositories to keep them apart",
+		GetWorktreeDir(e.Name), e.Repository)
+}
+
+func projectRecordsDir() string {
+	return filepath.Join(GetWorktreeBaseDir(), ".wt", "projects")
+}
+
+func projectRecordPath(name string) string {
+	return filepath.Join(projectRecordsDir(), filepath.FromSlash(name)+".json")
+}
+
+// LoadProjectRecord returns the record of the named project, or nil if wt
+// has none.
+func LoadProjectRecord(name string) (*ProjectRecord, error) {
+	data, err := os.ReadFile(projectRecordPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read project record: %w", err)
+	}
+
+	var record ProjectRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse project record: %w", err)
+	}
+	return &record, nil
+}
+
+// ListProjectRecords returns the records of every project, sorted by name.
+func ListProjectRecords() ([]ProjectRecord, error) {
+	var records []ProjectRecord
+	err := filepath.WalkDir(projectRecordsDir(), func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var record ProjectRecord
+		// Skip unreadable records rather than failing every listing
+		if json.Unmarshal(data, &record) == nil && record.Name != "" {
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project records: %w", err)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+	return records, nil
+}
+
+// RegisterProject records that the named project belongs to the current
+// repository. It fails with a ProjectCollisionError when the project's
+// worktree directory already belongs to another repository.
+func RegisterProject(name string) error {
+	mainPath, err := MainRepositoryPath()
+	if err != nil {
+		return err
+	}
+
+	if err := checkProjectOwner(name, mainPath); err != nil {
+		return err
+	}
+
+	record, err := LoadProjectRecord(name)
+	if err != nil {
+		return err
+	}
+	if record != nil && record.Repository == mainPath {
+		return nil
+	}
+
+	path := projectRecordPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create project record directory: %w", err)
+	}
+	data, err := json.MarshalIndent(ProjectRecord{Name: name, Repository: mainPath}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode project record: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write project record: %w", err)
+	}
+	return nil
+}
+
+// CheckProjectOwner fails with a ProjectCollisionError when the named
+// project's worktree directory belongs to another repository than the
+// current one.
+func CheckProjectOwner(name string) error {
+	mainPath, err := MainRepositoryPath()
+	if err != nil {
+		return err
+	}
+	return checkProjectOwner(name, mainPath)
+}
+
+func checkProjectOwner(name, mainPath string) error {
+	if owner := ProjectOwner(name); owner != "" && canonicalPath(owner) != canonicalPath(mainPath) {
+		return &ProjectCollisionError{Name: name, Repository: owner}
+	}
+	return nil
+}
+
+// UnregisterProject deletes the record of the named project, if any.
+func UnregisterProject(name string) error {
+	path := projectRecordPath(name)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove project record: %w", err)
+	}
+	RemoveEmptyParents(path, projectRecordsDir())
+	return nil
+}
+
+// ProjectOwner returns the main worktree of the repository the named
+// project's worktrees belong to, or "" when that's unknown. The project
+// record is trusted while its repository still exists; otherwise the
+// project's worktrees are asked, which also catches projects created before
+// wt kept records.
+func ProjectOwner(name string) string {
+	if record, err := LoadProjectRecord(name); err == nil && record != nil {
+		if _, err := os.Stat(record.Repository); err == nil {
+			return record.Repository
+		}
+	}
+
+	worktree, ok := findLinkedWorktree(GetWorktreeDir(name))
+	if !ok {
+		return ""
+	}
+	output, err := run.Output(runner.Command("git", "-C", worktree, "rev-parse", "--path-format=absolute", "--git-common-dir"))
+	if err != nil {
+		return ""
+	}
+	gitCommonDir := strings.TrimSpace(string(output))
+	if filepath.Base(gitCommonDir) != ".git" {
+		return ""
+	}
+	return filepath.Dir(gitCommonDir)
+}
+
+// WorktreeMove is a worktree to refile from one project's directory into
+// another's, keeping its path within the project directory.
+type WorktreeMove struct {
+	Worktree Worktree
+	// From is the project the worktree is filed under now, and Name its
+	// path relative to that project's directory
+	From    string
+	Name    string
+	NewPath string
+}
+
+// PlanProjectMigration returns the moves refiling the current repository's
+// worktrees that live in the directories of the projects in from under the
+// project to. Worktrees elsewhere, including those of other repositories in
+// the same directories, are left out.
+func PlanProjectMigration(to string, from []string) ([]WorktreeMove, error) {
+	mainPath, err := MainRepositoryPath()
+	if err != nil {
+		return nil, err
+	}
+	worktrees, err := ListGitWorktrees(mainPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []WorktreeMove
+	for _, wt := range worktrees {
+		if wt.IsMain || wt.Bare {
+			continue
+		}
+		path := canonicalPath(wt.Path)
+		if rel, err := filepath.Rel(canonicalPath(GetWorktreeDir(to)), path); err == nil && !strings.HasPrefix(rel, "..") {
+			continue
+		}
+		for _, name := range from {
+			rel, err := filepath.Rel(canonicalPath(GetWorktreeDir(name)), path)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			wt.Name = filepath.ToSlash(rel)
+			moves = append(moves, WorktreeMove{
+				Worktree: wt,
+				From:     name,
+				Name:     wt.Name,
+				NewPath:  filepath.Join(GetWorktreeDir(to), rel),
+			})
+			break
+		}
+	}
+	return moves, nil
+}
+
+// MoveWorktreeDir moves the directory of a worktree of the current repository
+// to newPath and runs `git worktree repair` so both sides point at the new
+// location. Unlike `git worktree move` this also handles worktrees with
+// submodules.
+func MoveWorktreeDir(worktreePath, newPath string) error {
+	mainPath, err := MainRepositoryPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("%s already exists", newPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(newPath), err)
+	}
+	if err := os.Rename(worktreePath, newPath); err != nil {
+		return fmt.Errorf("failed to move worktree: %w", err)
+	}
+
+	output, err := run.CombinedOutput(runner.Command("git", "-C", mainPath, "worktree", "repair", newPath))
+	if err != nil {
+		return fmt.Errorf("moved %s but 'git worktree repair' failed: %s", newPath, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
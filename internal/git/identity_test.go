@@ -0,0 +1,41 @@
+package git
+
+import "testing"
+
+func TestParseRemoteIdentity(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:acme/api.git", "github.com/acme/api"},
+		{"https://github.com/Acme/API", "github.com/acme/api"},
+		{"https://user@gitlab.example.com:8443/group/sub/api.git/", "gitlab.example.com/group/sub/api"},
+		{"ssh://git@github.com:22/acme/api.git", "github.com/acme/api"},
+		{"github.com:acme/api", "github.com/acme/api"},
+		{"/srv/git/api.git", ""},
+		{"../api", ""},
+		{"file:///srv/git/api.git", ""},
+	}
+	for _, tt := range tests {
+		if got := parseRemoteIdentity(tt.url); got != tt.want {
+			t.Errorf("parseRemoteIdentity(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeIdentity(t *testing.T) {
+	tests := []struct {
+		identity string
+		want     string
+	}{
+		{"work/api", "work/api"},
+		{"/work//api/", "work/api"},
+		{".config/wt repo", "config/wt_repo"},
+		{"../api", "api"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeIdentity(tt.identity); got != tt.want {
+			t.Errorf("sanitizeIdentity(%q) = %q, want %q", tt.identity, got, tt.want)
+		}
+	}
+}
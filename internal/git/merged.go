@@ -0,0 +1,98 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+const (
+	MergedReasonMerged       = "merged"
+	MergedReasonSquashMerged = "squash-merged"
+	MergedReasonUpstreamGone = "upstream gone"
+)
+
+// BranchMergedReason reports whether branchName is done, returning why:
+// it is an ancestor of base, its changes landed in base as a squash or
+// cherry-pick (detected via patch-id comparison), or its upstream branch was
+// deleted on the remote. An empty reason means the branch is not merged.
+//
+// A branch pointing at the same commit as base is treated as not merged: it
+// is indistinguishable from a freshly created branch with no work yet.
+func BranchMergedReason(repoPath, branchName, base string) (string, error) {
+	branchRef := "refs/heads/" + branchName
+
+	output, err := run.Output(runner.Command("git", "-C", repoPath, "rev-parse", branchRef, base))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s and %s: %w", branchName, base, err)
+	}
+	if shas := strings.Fields(string(output)); len(shas) == 2 && shas[0] == shas[1] {
+		return "", nil
+	}
+
+	if err := run.Run(runner.Command("git", "-C", repoPath, "merge-base", "--is-ancestor", branchRef, base)); err == nil {
+		return MergedReasonMerged, nil
+	} else if runner.ExitCode(err) != 1 {
+		return "", fmt.Errorf("failed to compare %s with %s: %w", branchName, base, err)
+	}
+
+	if patchesInBase(repoPath, base, branchRef) {
+		return MergedReasonSquashMerged, nil
+	}
+
+	// Squash merges combine all commits into one, so compare the branch's
+	// cumulative diff as a single commit as well.
+	if squashed, err := squashedCommit(repoPath, branchRef, base); err == nil && patchesInBase(repoPath, base, squashed) {
+		return MergedReasonSquashMerged, nil
+	}
+
+	output, err = run.Output(runner.Command("git", "-C", repoPath, "for-each-ref", "--format=%(upstream:track)", branchRef))
+	if err == nil && strings.TrimSpace(string(output)) == "[gone]" {
+		return MergedReasonUpstreamGone, nil
+	}
+
+	return "", nil
+}
+
+// patchesInBase reports whether every commit in ref that's missing from base
+// has an equivalent patch in base, according to `git cherry`.
+func patchesInBase(repoPath, base, ref string) bool {
+	output, err := run.Output(runner.Command("git", "-C", repoPath, "cherry", base, ref))
+	if err != nil {
+		return false
+	}
+
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return false
+	}
+	for _, line := range strings.Split(trimmed, "\n") {
+		if !strings.HasPrefix(line, "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// squashedCommit creates a dangling commit holding ref's tree on top of its
+// merge base with base, equivalent to squashing the branch.
+func squashedCommit(repoPath, ref, base string) (string, error) {
+	output, err := run.Output(runner.Command("git", "-C", repoPath, "merge-base", base, ref))
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base: %w", err)
+	}
+	mergeBase := strings.TrimSpace(string(output))
+
+	cmd := runner.Command("git", "-C", repoPath, "commit-tree", ref+"^{tree}", "-p", mergeBase, "-m", "wt squash check")
+	// Identity doesn't affect patch ids; set one so this works without user config
+	cmd.Env = []string{
+		"GIT_AUTHOR_NAME=wt", "GIT_AUTHOR_EMAIL=wt@localhost",
+		"GIT_COMMITTER_NAME=wt", "GIT_COMMITTER_EMAIL=wt@localhost",
+	}
+	output, err = run.Output(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to create squash commit: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
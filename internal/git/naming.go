@@ -0,0 +1,99 @@
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree directory naming schemes, chosen with the worktree_naming config key.
+const (
+	// NamingFlat puts worktrees directly in the repository's worktree
+	// directory, with "/" and other unsafe characters replaced by "_"
+	NamingFlat = "flat"
+	// NamingNested keeps "/", so feature/login lives in feature/login
+	NamingNested = "nested"
+	// NamingHashed is flat, with a hash of the branch appended when the flat
+	// name is taken
+	NamingHashed = "hashed"
+)
+
+// maxNestingDepth is how many directories deep nested worktrees are looked for.
+const maxNestingDepth = 4
+
+// WorktreeDirName returns the directory, relative to the repository's
+// worktree directory and with "/" separators, a worktree called name gets
+// under scheme.
+func WorktreeDirName(name, scheme string) string {
+	if scheme != NamingNested {
+		return SanitizeBranchName(name)
+	}
+	var segments []string
+	for _, segment := range strings.Split(name, "/") {
+		if segment != "" && segment != "." && segment != ".." {
+			segments = append(segments, SanitizeBranchName(segment))
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// HashedWorktreeDirName appends a short hash of branch to dir, telling apart
+// branches such as feature/a_b and feature_a/b that sanitize to the same name.
+func HashedWorktreeDirName(dir, branch string) string {
+	sum := sha1.Sum([]byte(branch))
+	return dir + "-" + hex.EncodeToString(sum[:])[:7]
+}
+
+// WorktreeDirConflict returns why dir, relative to the worktree directory of
+// repoName, can't hold a new worktree: another worktree already lives there,
+// in a directory containing it, or inside it, or the directory exists. It
+// returns nil when dir is free.
+func WorktreeDirConflict(repoName, dir string) error {
+	worktreeDir := GetWorktreeDir(repoName)
+	path := filepath.Join(worktreeDir, filepath.FromSlash(dir))
+
+	worktrees, err := ListWorktrees(repoName)
+	if err != nil {
+		return err
+	}
+	target := canonicalPath(path)
+	for _, wt := range worktrees {
+		existing := canonicalPath(wt.Path)
+		switch {
+		case existing == target:
+			return fmt.Errorf("directory '%s' is already used by the worktree for %s", dir, worktreeOwner(wt))
+		case strings.HasPrefix(target, existing+string(filepath.Separator)):
+			return fmt.Errorf("directory '%s' would be inside the worktree for %s at %s", dir, worktreeOwner(wt), wt.Path)
+		case strings.HasPrefix(existing, target+string(filepath.Separator)):
+			return fmt.Errorf("directory '%s' would contain the worktree for %s at %s", dir, worktreeOwner(wt), wt.Path)
+		}
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("directory %s already exists but isn't a worktree; move it away or run 'wt prune'", path)
+	}
+	return nil
+}
+
+func worktreeOwner(wt Worktree) string {
+	if wt.Detached {
+		return "a detached HEAD"
+	}
+	return fmt.Sprintf("branch '%s'", wt.Branch)
+}
+
+// RemoveEmptyParents removes the directories between path and stop that
+// were left empty, e.g. feature/ after deleting the nested worktree
+// feature/login. stop itself is kept.
+func RemoveEmptyParents(path, stop string) {
+	stop = filepath.Clean(stop)
+	for dir := filepath.Dir(filepath.Clean(path)); strings.HasPrefix(dir, stop+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		// Remove fails on directories that aren't empty
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
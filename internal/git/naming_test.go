@@ -0,0 +1,31 @@
+package git
+
+import "testing"
+
+func TestWorktreeDirName(t *testing.T) {
+	tests := []struct {
+		name, scheme, want string
+	}{
+		{"feature/login", NamingFlat, "feature_login"},
+		{"feature/login", NamingHashed, "feature_login"},
+		{"feature/login", NamingNested, "feature/login"},
+		{"feature//a:b/", NamingNested, "feature/a_b"},
+		{"../escape", NamingNested, "escape"},
+	}
+	for _, tt := range tests {
+		if got := WorktreeDirName(tt.name, tt.scheme); got != tt.want {
+			t.Errorf("WorktreeDirName(%q, %q) = %q, want %q", tt.name, tt.scheme, got, tt.want)
+		}
+	}
+}
+
+func TestHashedWorktreeDirNameTellsCollidingBranchesApart(t *testing.T) {
+	a := HashedWorktreeDirName(SanitizeBranchName("feature/a_b"), "feature/a_b")
+	b := HashedWorktreeDirName(SanitizeBranchName("feature_a/b"), "feature_a/b")
+	if a == b {
+		t.Errorf("both branches hash to %q", a)
+	}
+	if again := HashedWorktreeDirName("feature_a_b", "feature/a_b"); again != a {
+		t.Errorf("hash isn't stable: %q then %q", a, again)
+	}
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// ListGitWorktrees returns every worktree git knows about for the repository
+// containing repoPath, parsed from `git worktree list --porcelain -z`. The
+// main worktree comes first.
+func ListGitWorktrees(repoPath string) ([]Worktree, error) {
+	output, err := run.Output(runner.Command("git", "-C", repoPath, "worktree", "list", "--porcelain", "-z"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list git worktrees: %w", err)
+	}
+	return parseWorktreePorcelain(string(output)), nil
+}
+
+// parseWorktreePorcelain parses NUL-terminated porcelain output. Each record
+// is a sequence of attribute lines ended by an empty line; Name is left empty
+// for the caller to fill in.
+func parseWorktreePorcelain(output string) []Worktree {
+	var worktrees []Worktree
+	var current *Worktree
+
+	for _, line := range strings.Split(output, "\x00") {
+		if line == "" {
+			current = nil
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			worktrees = append(worktrees, Worktree{Path: value, IsMain: len(worktrees) == 0})
+			current = &worktrees[len(worktrees)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.BranchRef = value
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "detached":
+			current.Detached = true
+		case "bare":
+			current.Bare = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+			current.PrunableReason = value
+		}
+	}
+
+	for i := range worktrees {
+		if worktrees[i].Detached {
+			worktrees[i].Branch = "detached@" + ShortSHA(worktrees[i].Head)
+		}
+	}
+
+	return worktrees
+}
+
+// ShortSHA abbreviates a commit hash to seven characters for display.
+func ShortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func porcelain(records ...[]string) string {
+	var b strings.Builder
+	for _, record := range records {
+		for _, line := range record {
+			b.WriteString(line + "\x00")
+		}
+		b.WriteString("\x00")
+	}
+	return b.String()
+}
+
+func TestParseWorktreePorcelain(t *testing.T) {
+	output := porcelain(
+		[]string{"worktree /src/app", "HEAD 1111111111111111111111111111111111111111", "branch refs/heads/main"},
+		[]string{"worktree /wt/app/feature", "HEAD 2222222222222222222222222222222222222222", "branch refs/heads/feature/x", "locked on usb disk"},
+		[]string{"worktree /wt/app/review", "HEAD 3333333333333333333333333333333333333333", "detached"},
+		[]string{"worktree /wt/app/gone", "HEAD 4444444444444444444444444444444444444444", "branch refs/heads/gone", "prunable gitdir file points to non-existent location"},
+		[]string{"worktree /tmp/locked", "HEAD 5555555555555555555555555555555555555555", "branch refs/heads/l", "locked"},
+	)
+
+	got := parseWorktreePorcelain(output)
+	want := []Worktree{
+		{Path: "/src/app", Head: "1111111111111111111111111111111111111111", BranchRef: "refs/heads/main", Branch: "main", IsMain: true},
+		{Path: "/wt/app/feature", Head: "2222222222222222222222222222222222222222", BranchRef: "refs/heads/feature/x", Branch: "feature/x", Locked: true, LockReason: "on usb disk"},
+		{Path: "/wt/app/review", Head: "3333333333333333333333333333333333333333", Branch: "detached@3333333", Detached: true},
+		{Path: "/wt/app/gone", Head: "4444444444444444444444444444444444444444", BranchRef: "refs/heads/gone", Branch: "gone", Prunable: true, PrunableReason: "gitdir file points to non-existent location"},
+		{Path: "/tmp/locked", Head: "5555555555555555555555555555555555555555", BranchRef: "refs/heads/l", Branch: "l", Locked: true},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorktreePorcelain() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseWorktreePorcelainBareRepository(t *testing.T) {
+	output := porcelain(
+		[]string{"worktree /src/app.git", "bare"},
+		[]string{"worktree /wt/app/main", "HEAD 1111111111111111111111111111111111111111", "branch refs/heads/main"},
+	)
+
+	got := parseWorktreePorcelain(output)
+	if len(got) != 2 {
+		t.Fatalf("got %d worktrees, want 2", len(got))
+	}
+	if !got[0].Bare || !got[0].IsMain {
+		t.Errorf("first worktree = %+v, want bare main worktree", got[0])
+	}
+	if got[1].IsMain || got[1].Branch != "main" {
+		t.Errorf("second worktree = %+v", got[1])
+	}
+}
+
+func TestParseStatusPorcelain(t *testing.T) {
+	output := strings.Join([]string{
+		"# branch.oid 1111111111111111111111111111111111111111",
+		"# branch.head feature",
+		"# branch.upstream origin/feature",
+		"# branch.ab +2 -3",
+		"1 M. N... 100644 100644 100644 aaa bbb staged.go",
+		"1 .M N... 100644 100644 100644 aaa bbb unstaged.go",
+		"1 MM N... 100644 100644 100644 aaa bbb both.go",
+		"2 R. N... 100644 100644 100644 aaa bbb R100 new.go\told.go",
+		"u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go",
+		"? untracked.txt",
+		"? other.txt",
+	}, "\n")
+
+	var status WorktreeStatus
+	parseStatusPorcelain(output, &status)
+
+	want := WorktreeStatus{Staged: 3, Unstaged: 2, Untracked: 2, Conflicted: 1, Upstream: "origin/feature", Ahead: 2, Behind: 3}
+	if status != want {
+		t.Errorf("parseStatusPorcelain() = %+v, want %+v", status, want)
+	}
+	if !status.IsDirty() {
+		t.Errorf("status should be dirty")
+	}
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// Refs that hosting services publish pull request heads under; {number} is
+// replaced with the pull request number.
+const (
+	GitHubPullRequestRef = "refs/pull/{number}/head"
+	GitLabPullRequestRef = "refs/merge-requests/{number}/head"
+)
+
+// PullRequestBranch returns the local branch a pull request is checked out
+// as, e.g. pr/123.
+func PullRequestBranch(number int) string {
+	return "pr/" + strconv.Itoa(number)
+}
+
+// PullRequestTrackingRef returns the ref a pull request's head is fetched
+// into, e.g. refs/wt/pr/origin/123. It lives outside refs/remotes so that
+// `git fetch --prune` doesn't delete it and it never clobbers a branch the
+// remote itself calls pr/123.
+func PullRequestTrackingRef(remote string, number int) string {
+	return "refs/wt/pr/" + remote + "/" + strconv.Itoa(number)
+}
+
+// ParsePullRequestTrackingRef splits a ref made by PullRequestTrackingRef
+// into its remote and pull request number. ok is false for other refs.
+func ParsePullRequestTrackingRef(ref string) (remote string, number int, ok bool) {
+	rest, found := strings.CutPrefix(ref, "refs/wt/pr/")
+	if !found {
+		return "", 0, false
+	}
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(rest[i+1:])
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return rest[:i], number, true
+}
+
+// PullRequestRefspec returns the refspec fetching pull request number from
+// a remote publishing it under ref (e.g. GitHubPullRequestRef) into its
+// tracking ref. Pull requests may be force-pushed, so it always updates.
+func PullRequestRefspec(ref, remote string, number int) (string, error) {
+	if !strings.Contains(ref, "{number}") {
+		return "", fmt.Errorf("pull request ref %q doesn't contain {number}", ref)
+	}
+	source := strings.ReplaceAll(ref, "{number}", strconv.Itoa(number))
+	return "+" + source + ":" + PullRequestTrackingRef(remote, number), nil
+}
+
+// DefaultPullRequestRef guesses where remoteURL's hosting service publishes
+// pull request heads: GitLab's merge request refs for GitLab URLs, GitHub's
+// pull request refs otherwise.
+func DefaultPullRequestRef(remoteURL string) string {
+	if strings.Contains(strings.ToLower(remoteURL), "gitlab") {
+		return GitLabPullRequestRef
+	}
+	return GitHubPullRequestRef
+}
+
+// GetRemoteURL returns the fetch URL of remote.
+func GetRemoteURL(remote string) (string, error) {
+	output, err := run.Output(runner.Command("git", "remote", "get-url", remote))
+	if err != nil {
+		return "", fmt.Errorf("unknown remote '%s'", remote)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
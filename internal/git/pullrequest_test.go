@@ -0,0 +1,43 @@
+package git
+
+import "testing"
+
+func TestPullRequestRefspec(t *testing.T) {
+	got, err := PullRequestRefspec(GitHubPullRequestRef, "upstream", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "+refs/pull/42/head:refs/wt/pr/upstream/42"; got != want {
+		t.Errorf("PullRequestRefspec() = %q, want %q", got, want)
+	}
+
+	if _, err := PullRequestRefspec("refs/pull/head", "origin", 1); err == nil {
+		t.Error("expected an error for a ref without {number}")
+	}
+}
+
+func TestParsePullRequestTrackingRef(t *testing.T) {
+	remote, number, ok := ParsePullRequestTrackingRef(PullRequestTrackingRef("upstream", 42))
+	if !ok || remote != "upstream" || number != 42 {
+		t.Errorf("ParsePullRequestTrackingRef() = %q, %d, %v, want upstream, 42, true", remote, number, ok)
+	}
+
+	for _, ref := range []string{"origin/main", "refs/remotes/origin/pr/42", "refs/wt/pr/origin/x", "refs/wt/pr/42"} {
+		if _, _, ok := ParsePullRequestTrackingRef(ref); ok {
+			t.Errorf("ParsePullRequestTrackingRef(%q) should fail", ref)
+		}
+	}
+}
+
+func TestDefaultPullRequestRef(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:acme/app.git":             GitHubPullRequestRef,
+		"https://gitlab.example.com/acme/app.git": GitLabPullRequestRef,
+		"/srv/git/app.git":                        GitHubPullRequestRef,
+	}
+	for url, want := range tests {
+		if got := DefaultPullRequestRef(url); got != want {
+			t.Errorf("DefaultPullRequestRef(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
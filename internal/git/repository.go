@@ -3,23 +3,29 @@ package git
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/runner"
 )
 
 func IsGitRepository() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	err := cmd.Run()
+	err := run.Run(runner.Command("git", "rev-parse", "--git-dir"))
 	return err == nil
 }
 
+// GetRepositoryName returns the name the current repository's worktrees are
+// filed under, following the repo_layout config value.
 func GetRepositoryName() (string, error) {
+	return RepositoryIdentity(config.GetRepoLayout())
+}
+
+// GetGitCommonDir returns the absolute path of the git directory shared by
+// the main repository and all of its worktrees.
+func GetGitCommonDir() (string, error) {
 	// First check if we're in a worktree by getting the common git dir
-	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
-	output, err := cmd.Output()
+	output, err := run.Output(runner.Command("git", "rev-parse", "--git-common-dir"))
 	if err != nil {
 		return "", fmt.Errorf("not in a git repository")
 	}
@@ -29,8 +35,7 @@ func GetRepositoryName() (string, error) {
 	// Convert to absolute path if relative
 	if !filepath.IsAbs(gitCommonDir) {
 		// Get the current working directory to resolve relative path
-		cmd = exec.Command("git", "rev-parse", "--show-toplevel")
-		topLevel, err := cmd.Output()
+		topLevel, err := run.Output(runner.Command("git", "rev-parse", "--show-toplevel"))
 		if err != nil {
 			return "", fmt.Errorf("not in a git repository")
 		}
@@ -38,15 +43,7 @@ func GetRepositoryName() (string, error) {
 		gitCommonDir = filepath.Join(topLevelPath, gitCommonDir)
 	}
 
-	// If the common dir ends with .git, we're in a worktree or the main repo
-	if strings.HasSuffix(gitCommonDir, ".git") {
-		// Get the parent directory of .git to find the main repository path
-		mainRepoPath := filepath.Dir(gitCommonDir)
-		return filepath.Base(mainRepoPath), nil
-	}
-
-	// Fallback: shouldn't normally reach here
-	return "", fmt.Errorf("unable to determine repository name")
+	return gitCommonDir, nil
 }
 
 func GetWorktreeBaseDir() string {
@@ -60,3 +57,12 @@ func GetWorktreeBaseDir() string {
 func GetWorktreeDir(repoName string) string {
 	return filepath.Join(GetWorktreeBaseDir(), repoName)
 }
+
+// GetUserName returns the user.name git commits with, or "" if it isn't set.
+func GetUserName() string {
+	output, err := run.Output(runner.Command("git", "config", "user.name"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,59 @@
+package git
+
+import "testing"
+
+func TestGetRepositoryName(t *testing.T) {
+	tests := []struct {
+		name      string
+		commonDir string
+		topLevel  string
+		want      string
+	}{
+		{name: "main worktree", commonDir: ".git", topLevel: "/src/my-app", want: "my-app"},
+		{name: "subdirectory", commonDir: "/src/my-app/.git", want: "my-app"},
+		{name: "linked worktree", commonDir: "/src/my-app/.git", topLevel: "/wt/my-app/feature", want: "my-app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFake(t)
+			fake.OnOutput("git rev-parse --git-common-dir", tt.commonDir+"\n")
+			fake.OnOutput("git rev-parse --show-toplevel", tt.topLevel+"\n")
+
+			got, err := GetRepositoryName()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetRepositoryName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRepositoryNameOutsideRepository(t *testing.T) {
+	fake := useFake(t)
+	fake.OnFail("git rev-parse", "fatal: not a git repository")
+
+	if _, err := GetRepositoryName(); err == nil {
+		t.Error("expected an error outside a repository")
+	}
+}
+
+func TestGetDefaultBranch(t *testing.T) {
+	fake := useFake(t)
+	fake.OnFail("git -C /repo symbolic-ref", "fatal: ref refs/remotes/origin/HEAD is not a symbolic ref")
+	fake.OnFail("git -C /repo rev-parse --verify --quiet refs/heads/main", "")
+	fake.OnOutput("git -C /repo rev-parse --verify --quiet refs/heads/master", "")
+
+	got, err := GetDefaultBranch("/repo")
+	if err != nil || got != "master" {
+		t.Errorf("GetDefaultBranch() = %q, %v; want master", got, err)
+	}
+
+	fake.OnOutput("git -C /repo symbolic-ref", "origin/trunk\n")
+	got, err = GetDefaultBranch("/repo")
+	if err != nil || got != "origin/trunk" {
+		t.Errorf("GetDefaultBranch() = %q, %v; want origin/trunk", got, err)
+	}
+}
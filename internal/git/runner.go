@@ -0,0 +1,14 @@
+package git
+
+import "github.com/todoengineering/wt/internal/runner"
+
+// run executes every git command; tests replace it with a runner.Fake.
+var run runner.Runner = runner.Exec{}
+
+// SetRunner replaces the runner used for git commands and returns the
+// previous one.
+func SetRunner(r runner.Runner) runner.Runner {
+	prev := run
+	run = r
+	return prev
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// CleanSparsePath normalizes a sparse-checkout directory given on the command
+// line or in config, e.g. "./services/api/" to "services/api". Cone mode
+// only takes directories inside the worktree.
+func CleanSparsePath(p string) (string, error) {
+	cleaned := path.Clean(strings.TrimSpace(p))
+	if cleaned == "." || cleaned == "" {
+		return "", fmt.Errorf("sparse path %q is the worktree root", p)
+	}
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("sparse path %q is outside the worktree", p)
+	}
+	return cleaned, nil
+}
+
+// sparseCheckout enables cone-mode sparse checkout limited to paths in a
+// worktree created with --no-checkout, then checks out its files.
+func sparseCheckout(worktreePath string, paths []string) error {
+	if err := SetSparsePaths(worktreePath, paths); err != nil {
+		return err
+	}
+	output, err := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "checkout"))
+	if err != nil {
+		return fmt.Errorf("failed to check out sparse paths: %s", string(output))
+	}
+	return nil
+}
+
+// SetSparsePaths replaces the directories checked out in worktreePath,
+// enabling cone-mode sparse checkout if needed. Only this worktree is
+// affected.
+func SetSparsePaths(worktreePath string, paths []string) error {
+	args := append([]string{"-C", worktreePath, "sparse-checkout", "set", "--cone", "--"}, paths...)
+	output, err := run.CombinedOutput(runner.Command("git", args...))
+	if err != nil {
+		return fmt.Errorf("failed to set sparse paths: %s", string(output))
+	}
+	return nil
+}
+
+// AddSparsePaths adds directories to a sparse worktree's checkout.
+func AddSparsePaths(worktreePath string, paths []string) error {
+	args := append([]string{"-C", worktreePath, "sparse-checkout", "add", "--"}, paths...)
+	output, err := run.CombinedOutput(runner.Command("git", args...))
+	if err != nil {
+		return fmt.Errorf("failed to add sparse paths: %s", string(output))
+	}
+	return nil
+}
+
+// ListSparsePaths returns the directories checked out in worktreePath. ok is
+// false when the worktree isn't a sparse checkout.
+func ListSparsePaths(worktreePath string) (paths []string, ok bool) {
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "sparse-checkout", "list"))
+	if err != nil {
+		return nil, false
+	}
+	return nonEmptyLines(string(output)), true
+}
@@ -0,0 +1,148 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+type WorktreeStatus struct {
+	Staged     int
+	Unstaged   int
+	Untracked  int
+	Conflicted int
+
+	Upstream string
+	Ahead    int
+	Behind   int
+
+	Base       string
+	BaseAhead  int
+	BaseBehind int
+
+	LastCommitSubject string
+	LastCommitTime    time.Time
+}
+
+func (s WorktreeStatus) IsDirty() bool {
+	return s.Staged+s.Unstaged+s.Untracked+s.Conflicted > 0
+}
+
+// GetWorktreeStatus collects working tree changes, upstream tracking and the
+// last commit for the worktree at worktreePath. When baseBranch is not empty,
+// ahead/behind counts against it are included as well.
+func GetWorktreeStatus(worktreePath, baseBranch string) (WorktreeStatus, error) {
+	var status WorktreeStatus
+
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "status", "--porcelain=v2", "--branch"))
+	if err != nil {
+		return status, fmt.Errorf("failed to get status for %s: %w", worktreePath, err)
+	}
+	parseStatusPorcelain(string(output), &status)
+
+	if baseBranch != "" {
+		ahead, behind, err := AheadBehind(worktreePath, "HEAD", baseBranch)
+		if err == nil {
+			status.Base = baseBranch
+			status.BaseAhead = ahead
+			status.BaseBehind = behind
+		}
+	}
+
+	output, err = run.Output(runner.Command("git", "-C", worktreePath, "log", "-1", "--format=%s%x00%ct"))
+	if err == nil {
+		parts := strings.SplitN(strings.TrimSpace(string(output)), "\x00", 2)
+		if len(parts) == 2 {
+			status.LastCommitSubject = parts[0]
+			if ts, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+				status.LastCommitTime = time.Unix(ts, 0)
+			}
+		}
+	}
+
+	return status, nil
+}
+
+func parseStatusPorcelain(output string, status *WorktreeStatus) {
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// Format: # branch.ab +<ahead> -<behind>
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			// Ordinary and renamed entries carry an XY field for index/worktree state
+			fields := strings.Fields(line)
+			if len(fields) < 2 || len(fields[1]) != 2 {
+				continue
+			}
+			if fields[1][0] != '.' {
+				status.Staged++
+			}
+			if fields[1][1] != '.' {
+				status.Unstaged++
+			}
+		case strings.HasPrefix(line, "u "):
+			status.Conflicted++
+		case strings.HasPrefix(line, "? "):
+			status.Untracked++
+		}
+	}
+}
+
+// AheadBehind returns how many commits ref has that base doesn't (ahead)
+// and how many base has that ref doesn't (behind).
+func AheadBehind(worktreePath, ref, base string) (int, int, error) {
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "rev-list", "--left-right", "--count", fmt.Sprintf("%s...%s", ref, base)))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %w", ref, base, err)
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %s", string(output))
+	}
+	ahead, _ := strconv.Atoi(fields[0])
+	behind, _ := strconv.Atoi(fields[1])
+	return ahead, behind, nil
+}
+
+// GetDefaultBranch returns the repository's base branch as a ref usable from
+// repoPath. It prefers the remote default (refs/remotes/origin/HEAD) and falls
+// back to a local main or master branch.
+func GetDefaultBranch(repoPath string) (string, error) {
+	if output, err := run.Output(runner.Command("git", "-C", repoPath, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")); err == nil {
+		if ref := strings.TrimSpace(string(output)); ref != "" {
+			return ref, nil
+		}
+	}
+
+	for _, candidate := range []string{"main", "master"} {
+		if err := run.Run(runner.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+candidate)); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("unable to determine default branch")
+}
+
+// IsRemoteDefaultBranch reports whether branchName is remote's default
+// branch (refs/remotes/<remote>/HEAD). When the remote's HEAD isn't known
+// locally, main and master are assumed to be.
+func IsRemoteDefaultBranch(remote, branchName string) bool {
+	output, err := run.Output(runner.Command("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD"))
+	if err == nil {
+		if ref := strings.TrimSpace(string(output)); ref != "" {
+			return ref == remote+"/"+branchName
+		}
+	}
+	return branchName == "main" || branchName == "master"
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// HasSubmodules reports whether the commit checked out in worktreePath has
+// submodules.
+func HasSubmodules(worktreePath string) bool {
+	return run.Run(runner.Command("git", "-C", worktreePath, "cat-file", "-e", "HEAD:.gitmodules")) == nil
+}
+
+// UpdateSubmodules initializes and checks out the submodules of the worktree
+// at worktreePath, recursively. Submodules the main repository has already
+// cloned are cloned with --reference to that copy and then dissociated, so
+// their objects are copied locally instead of being downloaded again.
+func UpdateSubmodules(worktreePath string) error {
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "rev-parse", "--path-format=absolute", "--git-common-dir"))
+	if err != nil {
+		return fmt.Errorf("failed to find repository of %s: %w", worktreePath, err)
+	}
+	modulesDir := filepath.Join(strings.TrimSpace(string(output)), "modules")
+
+	submodules, err := listSubmodules(worktreePath)
+	if err != nil {
+		return err
+	}
+	for _, sm := range submodules {
+		args := []string{"-C", worktreePath, "submodule", "update", "--init"}
+		reference := filepath.Join(modulesDir, sm.name)
+		if info, err := os.Stat(reference); err == nil && info.IsDir() {
+			args = append(args, "--reference", reference, "--dissociate")
+		}
+		args = append(args, "--", sm.path)
+		if output, err := run.CombinedOutput(runner.Command("git", args...)); err != nil {
+			return fmt.Errorf("failed to update submodule %s: %s", sm.path, strings.TrimSpace(string(output)))
+		}
+	}
+
+	// Nested submodules have no reference to reuse
+	if output, err := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "submodule", "update", "--init", "--recursive")); err != nil {
+		return fmt.Errorf("failed to update submodules: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+type submodule struct {
+	name string
+	path string
+}
+
+// listSubmodules returns the top-level submodules declared in .gitmodules.
+func listSubmodules(worktreePath string) ([]submodule, error) {
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "config", "--blob", "HEAD:.gitmodules", "--get-regexp", `^submodule\..*\.path$`))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .gitmodules: %w", err)
+	}
+	return parseSubmodulePaths(string(output)), nil
+}
+
+// parseSubmodulePaths parses `git config --get-regexp` output of
+// submodule.<name>.path keys, where names may contain dots.
+func parseSubmodulePaths(output string) []submodule {
+	var submodules []submodule
+	for _, line := range nonEmptyLines(output) {
+		key, value, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "submodule."), ".path")
+		submodules = append(submodules, submodule{name: name, path: value})
+	}
+	return submodules
+}
+
+// UsesLFS reports whether any .gitattributes file of the commit checked out
+// in worktreePath routes files through Git LFS.
+func UsesLFS(worktreePath string) bool {
+	return run.Run(runner.Command("git", "-C", worktreePath, "grep", "-q", "filter=lfs", "HEAD", "--", ":(glob)**/.gitattributes")) == nil
+}
+
+// IsLFSInstalled reports whether the git-lfs extension is available.
+func IsLFSInstalled() bool {
+	return run.Run(runner.Command("git", "lfs", "version")) == nil
+}
+
+// PullLFS downloads and checks out the LFS files of the worktree at
+// worktreePath. Objects are stored in the repository's common directory, so
+// files already fetched for another worktree aren't downloaded again.
+func PullLFS(worktreePath string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "lfs", "pull"))
+	if err != nil {
+		return fmt.Errorf("failed to pull LFS files: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
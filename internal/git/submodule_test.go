@@ -0,0 +1,42 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSubmodulePaths(t *testing.T) {
+	output := "submodule.vendor/lib.path vendor/lib\nsubmodule.docs.v2.path site/docs\n"
+	want := []submodule{{name: "vendor/lib", path: "vendor/lib"}, {name: "docs.v2", path: "site/docs"}}
+	if got := parseSubmodulePaths(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSubmodulePaths() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdateSubmodulesReusesMainRepositoryModules(t *testing.T) {
+	commonDir := t.TempDir()
+	reference := filepath.Join(commonDir, "modules", "vendor/lib")
+	if err := os.MkdirAll(reference, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := useFake(t)
+	fake.OnOutput("git -C /wt/app/feat rev-parse --path-format=absolute --git-common-dir", commonDir+"\n")
+	fake.OnOutput("git -C /wt/app/feat config --blob HEAD:.gitmodules", "submodule.vendor/lib.path vendor/lib\nsubmodule.tools.path tools\n")
+	fake.OnOutput("git -C /wt/app/feat submodule update", "")
+
+	if err := UpdateSubmodules("/wt/app/feat"); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []string{
+		"git -C /wt/app/feat submodule update --init --reference " + reference + " --dissociate -- vendor/lib",
+		"git -C /wt/app/feat submodule update --init -- tools",
+		"git -C /wt/app/feat submodule update --init --recursive",
+	} {
+		if !fake.Ran(c) {
+			t.Errorf("expected %q to run; calls: %q", c, fake.Calls())
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// ErrConflictAborted is returned when a rebase or merge stopped on conflicts
+// and was aborted, leaving the worktree as it was before the operation.
+var ErrConflictAborted = errors.New("conflicts found, operation aborted")
+
+// RebaseWorktree rebases the branch checked out in worktreePath onto ref.
+// On conflicts the rebase is aborted so the worktree is never left mid-rebase.
+func RebaseWorktree(worktreePath, ref string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "rebase", ref))
+	if err == nil {
+		return nil
+	}
+
+	if isRebaseInProgress(worktreePath) {
+		if abortOutput, abortErr := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "rebase", "--abort")); abortErr != nil {
+			return fmt.Errorf("failed to abort rebase onto %s: %s", ref, string(abortOutput))
+		}
+		return fmt.Errorf("rebase onto %s: %w", ref, ErrConflictAborted)
+	}
+
+	return fmt.Errorf("failed to rebase onto %s: %s", ref, string(output))
+}
+
+// MergeIntoWorktree merges ref into the branch checked out in worktreePath.
+// On conflicts the merge is aborted so the worktree is never left mid-merge.
+func MergeIntoWorktree(worktreePath, ref string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "merge", "--no-edit", ref))
+	if err == nil {
+		return nil
+	}
+
+	if isMergeInProgress(worktreePath) {
+		if abortOutput, abortErr := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "merge", "--abort")); abortErr != nil {
+			return fmt.Errorf("failed to abort merge of %s: %s", ref, string(abortOutput))
+		}
+		return fmt.Errorf("merge of %s: %w", ref, ErrConflictAborted)
+	}
+
+	return fmt.Errorf("failed to merge %s: %s", ref, string(output))
+}
+
+func isRebaseInProgress(worktreePath string) bool {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		output, err := run.Output(runner.Command("git", "-C", worktreePath, "rev-parse", "--path-format=absolute", "--git-path", name))
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(strings.TrimSpace(string(output))); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isMergeInProgress(worktreePath string) bool {
+	return run.Run(runner.Command("git", "-C", worktreePath, "rev-parse", "--quiet", "--verify", "MERGE_HEAD")) == nil
+}
+
+// ErrNotFastForward is returned by FastForwardBranch when the branch has
+// commits that ref doesn't, e.g. after a force push or local commits.
+var ErrNotFastForward = errors.New("not a fast-forward")
+
+// FastForwardBranch moves the local branch branchName forward to ref,
+// refusing to rewrite history. If the branch is checked out in a worktree,
+// the worktree is updated as well.
+func FastForwardBranch(branchName, ref string) error {
+	if err := run.Run(runner.Command("git", "merge-base", "--is-ancestor", "refs/heads/"+branchName, ref)); err != nil {
+		if runner.ExitCode(err) == 1 {
+			return fmt.Errorf("can't fast-forward %s to %s: %w", branchName, ref, ErrNotFastForward)
+		}
+		return fmt.Errorf("failed to compare %s with %s: %w", branchName, ref, err)
+	}
+
+	if checkedOut, worktreePath := GitWorktreeExistsForBranch(branchName); checkedOut {
+		output, err := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "merge", "--ff-only", ref))
+		if err != nil {
+			return fmt.Errorf("failed to fast-forward %s to %s: %s", branchName, ref, string(output))
+		}
+		return nil
+	}
+
+	output, err := run.CombinedOutput(runner.Command("git", "update-ref", "refs/heads/"+branchName, ref))
+	if err != nil {
+		return fmt.Errorf("failed to fast-forward %s to %s: %s", branchName, ref, string(output))
+	}
+	return nil
+}
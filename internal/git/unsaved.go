@@ -0,0 +1,76 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+// UnsavedWork describes what would be lost by removing a worktree.
+type UnsavedWork struct {
+	// DirtyFiles are `git status --short` lines for modified, staged and untracked files
+	DirtyFiles []string
+	// UnpushedCommits are "<sha> <subject>" lines for commits that exist on no
+	// remote-tracking branch and no other local branch
+	UnpushedCommits []string
+	// Stashes are stash entries created on the worktree's branch. They survive
+	// worktree removal but are easy to forget about.
+	Stashes []string
+}
+
+func (u UnsavedWork) IsEmpty() bool {
+	return len(u.DirtyFiles) == 0 && len(u.UnpushedCommits) == 0
+}
+
+// InspectUnsavedWork reports uncommitted changes, untracked files and commits
+// that exist only in the worktree at worktreePath.
+func InspectUnsavedWork(worktreePath, branchName string) (UnsavedWork, error) {
+	var work UnsavedWork
+
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "status", "--short", "--untracked-files=all"))
+	if err != nil {
+		return work, fmt.Errorf("failed to get status for %s: %w", worktreePath, err)
+	}
+	work.DirtyFiles = nonEmptyLines(string(output))
+
+	args := []string{"-C", worktreePath, "log", "--format=%h %s", "HEAD", "--not"}
+	if isBranchName(branchName) {
+		// Commits reachable from the worktree's own branch would be lost with it
+		args = append(args, "--exclude="+branchName)
+	}
+	args = append(args, "--branches", "--remotes")
+	output, err = run.Output(runner.Command("git", args...))
+	if err != nil {
+		return work, fmt.Errorf("failed to list unpushed commits for %s: %w", worktreePath, err)
+	}
+	work.UnpushedCommits = nonEmptyLines(string(output))
+
+	if isBranchName(branchName) {
+		output, err = run.Output(runner.Command("git", "-C", worktreePath, "stash", "list", "--format=%gd %gs"))
+		if err == nil {
+			for _, line := range nonEmptyLines(string(output)) {
+				// Subjects look like "WIP on <branch>: ..." or "On <branch>: ..."
+				if strings.Contains(line, " on "+branchName+":") || strings.Contains(line, " On "+branchName+":") {
+					work.Stashes = append(work.Stashes, line)
+				}
+			}
+		}
+	}
+
+	return work, nil
+}
+
+func isBranchName(branchName string) bool {
+	return branchName != "" && branchName != "unknown" && !strings.HasPrefix(branchName, "detached")
+}
+
+func nonEmptyLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -4,43 +4,55 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/runner"
 )
 
 type Worktree struct {
 	Name   string
 	Path   string
 	Branch string
+
+	// Fields below come from `git worktree list --porcelain`
+	Head           string
+	BranchRef      string
+	Detached       bool
+	Bare           bool
+	IsMain         bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool
+	PrunableReason string
 }
 
+// ListWorktrees returns the linked worktrees of the repository named
+// repoName, as reported by git. This includes worktrees created outside the
+// worktree directory with plain `git worktree add`; the main worktree is
+// excluded.
 func ListWorktrees(repoName string) ([]Worktree, error) {
-	worktreeDir := GetWorktreeDir(repoName)
-
-	if _, err := os.Stat(worktreeDir); os.IsNotExist(err) {
+	repoPath, ok := findRepositoryPath(repoName)
+	if !ok {
 		return []Worktree{}, nil
 	}
 
-	entries, err := os.ReadDir(worktreeDir)
+	all, err := ListGitWorktrees(repoPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read worktree directory: %w", err)
+		return nil, err
 	}
 
+	worktreeDir := canonicalPath(GetWorktreeDir(repoName))
+
 	var worktrees []Worktree
-	for _, entry := range entries {
-		if entry.IsDir() {
-			worktreePath := filepath.Join(worktreeDir, entry.Name())
-			branch := GetWorktreeBranch(worktreePath)
-			worktrees = append(worktrees, Worktree{
-				Name:   entry.Name(),
-				Path:   worktreePath,
-				Branch: branch,
-			})
+	for _, wt := range all {
+		if wt.IsMain || wt.Bare {
+			continue
 		}
+		wt.Name = worktreeName(worktreeDir, wt.Path)
+		worktrees = append(worktrees, wt)
 	}
 
 	sort.Slice(worktrees, func(i, j int) bool {
@@ -50,17 +62,85 @@ func ListWorktrees(repoName string) ([]Worktree, error) {
 	return worktrees, nil
 }
 
+// findRepositoryPath returns a directory inside the repository named
+// repoName: the current directory when it belongs to that repository, the
+// repository recorded for the project, or otherwise any worktree under the
+// repository's worktree directory.
+func findRepositoryPath(repoName string) (string, bool) {
+	if IsGitRepository() {
+		if name, err := GetRepositoryName(); err == nil && name == repoName {
+			return ".", true
+		}
+	}
+
+	if record, err := LoadProjectRecord(repoName); err == nil && record != nil {
+		if _, err := os.Stat(record.Repository); err == nil {
+			return record.Repository, true
+		}
+	}
+
+	return findLinkedWorktree(GetWorktreeDir(repoName))
+}
+
+// findLinkedWorktree returns any worktree under root. Linked worktrees have a
+// .git file pointing back at the repository; nested worktrees
+// (worktree_naming = "nested") sit a few levels down.
+func findLinkedWorktree(root string) (string, bool) {
+	var found string
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == root {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			found = path
+			return filepath.SkipAll
+		}
+		// Don't wander through the contents of leftover directories
+		rel, _ := filepath.Rel(root, path)
+		if strings.HasPrefix(d.Name(), ".") || strings.Count(rel, string(filepath.Separator)) >= maxNestingDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	return found, found != ""
+}
+
+// WorktreeName returns the name of the worktree of repoName at worktreePath,
+// as ListWorktrees reports it, e.g. feature/login for a nested worktree.
+func WorktreeName(repoName, worktreePath string) string {
+	return worktreeName(canonicalPath(GetWorktreeDir(repoName)), worktreePath)
+}
+
+// InWorktreeDir reports whether worktreePath lies inside the worktree
+// directory of repoName.
+func InWorktreeDir(repoName, worktreePath string) bool {
+	rel, err := filepath.Rel(canonicalPath(GetWorktreeDir(repoName)), canonicalPath(worktreePath))
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+// worktreeName derives a display name from a worktree path: the path relative
+// to the repository's worktree directory, or the directory name for
+// worktrees living elsewhere.
+func worktreeName(worktreeDir, worktreePath string) string {
+	rel, err := filepath.Rel(worktreeDir, canonicalPath(worktreePath))
+	if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.Base(worktreePath)
+}
+
+// WorktreeExistsForBranch returns the worktree of repoName that has
+// branchName checked out. Branches are compared exactly rather than through
+// directory names, which several branches can share once sanitized.
 func WorktreeExistsForBranch(repoName, branchName string) (bool, *Worktree) {
 	worktrees, err := ListWorktrees(repoName)
 	if err != nil {
 		return false, nil
 	}
 
-	// Sanitize branch name to match how it would be used as a worktree directory
-	sanitizedBranch := SanitizeBranchName(branchName)
-
 	for _, wt := range worktrees {
-		if wt.Name == sanitizedBranch || wt.Name == branchName {
+		if !wt.Detached && wt.Branch == branchName {
 			return true, &wt
 		}
 	}
@@ -86,26 +166,14 @@ func SanitizeBranchName(branchName string) string {
 }
 
 func GitWorktreeExistsForBranch(branchName string) (bool, string) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	output, err := cmd.Output()
+	worktrees, err := ListGitWorktrees(".")
 	if err != nil {
 		return false, ""
 	}
 
-	lines := strings.Split(string(output), "\n")
-	var currentPath string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "worktree ") {
-			currentPath = strings.TrimPrefix(line, "worktree ")
-		} else if strings.HasPrefix(line, "branch ") {
-			branch := strings.TrimPrefix(line, "branch ")
-			// Remove refs/heads/ prefix if present
-			branch = strings.TrimPrefix(branch, "refs/heads/")
-			if branch == branchName {
-				return true, currentPath
-			}
+	for _, wt := range worktrees {
+		if !wt.Detached && wt.Branch == branchName {
+			return true, wt.Path
 		}
 	}
 
@@ -118,6 +186,10 @@ type Project struct {
 	Worktrees []Worktree
 }
 
+// ListAllProjects returns the projects under the worktree base directory
+// that have worktrees: those wt has records of, whose names may span several
+// directories (e.g. github.com/acme/api), and the directories directly under
+// the base directory.
 func ListAllProjects() ([]Project, error) {
 	baseDir := GetWorktreeBaseDir()
 
@@ -130,27 +202,49 @@ func ListAllProjects() ([]Project, error) {
 		return nil, fmt.Errorf("failed to read worktree base directory: %w", err)
 	}
 
-	var projects []Project
+	records, err := ListProjectRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	// Top-level directories of multi-directory names aren't projects
+	parents := make(map[string]bool)
+	for _, record := range records {
+		names = append(names, record.Name)
+		if first, _, nested := strings.Cut(record.Name, "/"); nested {
+			parents[first] = true
+		}
+	}
 	for _, entry := range entries {
-		if entry.IsDir() {
-			projectName := entry.Name()
-			projectPath := filepath.Join(baseDir, projectName)
+		// Hidden directories hold wt's own state (e.g. the trash), not projects
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && !parents[entry.Name()] {
+			names = append(names, entry.Name())
+		}
+	}
 
-			// List worktrees for this project
-			worktrees, err := ListWorktrees(projectName)
-			if err != nil {
-				// Skip projects we can't read worktrees for
-				continue
-			}
+	var projects []Project
+	seen := make(map[string]bool)
+	for _, projectName := range names {
+		if seen[projectName] {
+			continue
+		}
+		seen[projectName] = true
 
-			// Only include projects that have at least one worktree
-			if len(worktrees) > 0 {
-				projects = append(projects, Project{
-					Name:      projectName,
-					Path:      projectPath,
-					Worktrees: worktrees,
-				})
-			}
+		// List worktrees for this project
+		worktrees, err := ListWorktrees(projectName)
+		if err != nil {
+			// Skip projects we can't read worktrees for
+			continue
+		}
+
+		// Only include projects that have at least one worktree
+		if len(worktrees) > 0 {
+			projects = append(projects, Project{
+				Name:      projectName,
+				Path:      GetWorktreeDir(projectName),
+				Worktrees: worktrees,
+			})
 		}
 	}
 
@@ -163,6 +257,26 @@ func ListAllProjects() ([]Project, error) {
 }
 
 func CreateWorktree(repoName, worktreeName, branchName string) (string, error) {
+	return CreateWorktreeWithOptions(repoName, worktreeName, branchName, WorktreeOptions{})
+}
+
+// CreateDetachedWorktree creates a worktree at commit without checking out a branch.
+func CreateDetachedWorktree(repoName, worktreeName, commit string) (string, error) {
+	return CreateWorktreeWithOptions(repoName, worktreeName, commit, WorktreeOptions{Detach: true})
+}
+
+// WorktreeOptions customizes how CreateWorktreeWithOptions checks out a worktree.
+type WorktreeOptions struct {
+	// Detach checks out the commit without a branch
+	Detach bool
+	// SparsePaths limits the checkout to these directories (cone mode)
+	SparsePaths []string
+}
+
+// CreateWorktreeWithOptions creates a worktree in the repository's worktree
+// directory with commitish checked out, and copies the configured files into
+// it.
+func CreateWorktreeWithOptions(repoName, worktreeName, commitish string, opts WorktreeOptions) (string, error) {
 	worktreeDir := GetWorktreeDir(repoName)
 	worktreePath := filepath.Join(worktreeDir, worktreeName)
 
@@ -177,25 +291,65 @@ func CreateWorktree(repoName, worktreeName, branchName string) (string, error) {
 	}
 
 	// Create the worktree
-	cmd := exec.Command("git", "worktree", "add", worktreePath, branchName)
-	output, err := cmd.CombinedOutput()
+	args := []string{"worktree", "add"}
+	if opts.Detach {
+		args = append(args, "--detach")
+	}
+	if len(opts.SparsePaths) > 0 {
+		// Files are checked out once the sparse paths are set
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, worktreePath, commitish)
+	output, err := run.CombinedOutput(runner.Command("git", args...))
 	if err != nil {
 		return "", fmt.Errorf("failed to create worktree: %s", string(output))
 	}
 
+	if len(opts.SparsePaths) > 0 {
+		if err := sparseCheckout(worktreePath, opts.SparsePaths); err != nil {
+			// Don't leave a worktree with nothing checked out behind
+			run.Run(runner.Command("git", "worktree", "remove", "--force", worktreePath))
+			return "", err
+		}
+	}
+
 	// Copy configured files from main repository to new worktree
 	if err := copyConfiguredFiles(worktreePath); err != nil {
 		// Log warning but don't fail the worktree creation
 		fmt.Fprintf(os.Stderr, "Warning: failed to copy some files: %v\n", err)
 	}
 
+	initWorktreeContent(worktreePath)
+
 	return worktreePath, nil
 }
 
+// initWorktreeContent fills in what `git worktree add` leaves out: submodule
+// checkouts and Git LFS files. Failures are only warnings, since the
+// worktree itself is usable and the steps can be rerun by hand.
+func initWorktreeContent(worktreePath string) {
+	if config.GetInitSubmodules() && HasSubmodules(worktreePath) {
+		fmt.Println("Initializing submodules...")
+		if err := UpdateSubmodules(worktreePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; run 'git submodule update --init --recursive' in the worktree\n", err)
+		}
+	}
+
+	if config.GetPullLFS() && UsesLFS(worktreePath) {
+		if !IsLFSInstalled() {
+			fmt.Fprintf(os.Stderr, "Warning: repository uses Git LFS but git-lfs isn't installed; LFS files are left as pointers\n")
+			return
+		}
+		fmt.Println("Pulling Git LFS files...")
+		if err := PullLFS(worktreePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; run 'git lfs pull' in the worktree\n", err)
+		}
+	}
+}
+
 func copyConfiguredFiles(worktreePath string) error {
 	// Get the main repository path (current directory)
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	output, err := run.Output(runner.Command("git", "rev-parse", "--show-toplevel"))
 	if err != nil {
 		return fmt.Errorf("failed to get repository root: %w", err)
 	}
@@ -283,10 +437,15 @@ func copyFile(src, dst string) error {
 	return err
 }
 
-func RemoveWorktree(worktreePath string) error {
-	// Use git worktree remove with --force to handle uncommitted changes
-	cmd := exec.Command("git", "worktree", "remove", "--force", worktreePath)
-	output, err := cmd.CombinedOutput()
+// RemoveWorktree removes the worktree at worktreePath. Without force, git
+// refuses to remove worktrees with uncommitted changes or untracked files.
+func RemoveWorktree(worktreePath string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, worktreePath)
+	output, err := run.CombinedOutput(runner.Command("git", args...))
 	if err != nil {
 		return fmt.Errorf("failed to remove worktree: %s", string(output))
 	}
@@ -296,8 +455,7 @@ func RemoveWorktree(worktreePath string) error {
 
 func IsMainWorktree(worktreePath string) (bool, error) {
 	// Get the main repository path
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	output, err := run.Output(runner.Command("git", "rev-parse", "--show-toplevel"))
 	if err != nil {
 		return false, fmt.Errorf("failed to get main repository path: %w", err)
 	}
@@ -320,12 +478,10 @@ func IsMainWorktree(worktreePath string) (bool, error) {
 
 func GetWorktreeBranch(worktreePath string) string {
 	// Change to the worktree directory and get the current branch
-	cmd := exec.Command("git", "-C", worktreePath, "branch", "--show-current")
-	output, err := cmd.Output()
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "branch", "--show-current"))
 	if err != nil {
 		// Fallback: try to get branch from HEAD
-		cmd = exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
-		output, err = cmd.Output()
+		output, err = run.Output(runner.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "HEAD"))
 		if err != nil {
 			return "unknown"
 		}
@@ -334,8 +490,7 @@ func GetWorktreeBranch(worktreePath string) string {
 	branch := strings.TrimSpace(string(output))
 	if branch == "HEAD" {
 		// Detached HEAD state, try to get the commit hash
-		cmd = exec.Command("git", "-C", worktreePath, "rev-parse", "--short", "HEAD")
-		output, err = cmd.Output()
+		output, err = run.Output(runner.Command("git", "-C", worktreePath, "rev-parse", "--short", "HEAD"))
 		if err != nil {
 			return "detached"
 		}
@@ -344,3 +499,244 @@ func GetWorktreeBranch(worktreePath string) string {
 
 	return branch
 }
+
+// ListOrphanedWorktreeDirs returns directories under the repository's worktree
+// directory that git doesn't know about, e.g. leftovers from a failed
+// `git worktree add`. Directories holding a .git entry are only orphans when
+// it points at a worktree git has dropped from this repository; the others,
+// such as worktrees of another clone sharing the directory, are returned as
+// foreign and must be left alone.
+func ListOrphanedWorktreeDirs(repoName string) (orphans, foreign []string, err error) {
+	worktreeDir := GetWorktreeDir(repoName)
+
+	entries, err := os.ReadDir(worktreeDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read worktree directory: %w", err)
+	}
+
+	registered, err := ListGitWorktrees(".")
+	if err != nil {
+		return nil, nil, err
+	}
+	commonDir, err := GetGitCommonDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scan := orphanScan{
+		known:        make(map[string]bool),
+		worktreesDir: filepath.Join(canonicalPath(commonDir), "worktrees"),
+	}
+	for _, wt := range registered {
+		scan.known[canonicalPath(wt.Path)] = true
+	}
+	scan.walk(worktreeDir, entries)
+	return scan.orphans, scan.foreign, nil
+}
+
+// orphanScan sorts the directories under a worktree directory into orphans
+// and foreign worktrees.
+type orphanScan struct {
+	known map[string]bool
+	// worktreesDir is where this repository keeps its worktrees' git dirs
+	worktreesDir     string
+	orphans, foreign []string
+}
+
+// walk classifies the entries of dir, descending into directories that hold
+// known worktrees or any other .git entry so only their leftovers are
+// orphaned.
+func (s *orphanScan) walk(dir string, entries []os.DirEntry) {
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		canonical := canonicalPath(path)
+		if s.known[canonical] {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			if s.isDropped(path) {
+				s.orphans = append(s.orphans, path)
+			} else {
+				s.foreign = append(s.foreign, path)
+			}
+			continue
+		}
+		if containsKnown(canonical, s.known) || containsGitEntry(path) {
+			if children, err := os.ReadDir(path); err == nil {
+				s.walk(path, children)
+			}
+			continue
+		}
+		s.orphans = append(s.orphans, path)
+	}
+}
+
+// isDropped reports whether the .git file of the worktree at path points into
+// this repository at a worktree git no longer has.
+func (s *orphanScan) isDropped(path string) bool {
+	data, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return false
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return false
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(path, gitDir)
+	}
+	gitDir = canonicalPath(gitDir)
+
+	rel, err := filepath.Rel(s.worktreesDir, gitDir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	_, err = os.Stat(gitDir)
+	return os.IsNotExist(err)
+}
+
+func containsKnown(dir string, known map[string]bool) bool {
+	for path := range known {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsGitEntry reports whether any directory below dir, at any depth, has
+// a .git file or directory.
+func containsGitEntry(dir string) bool {
+	found := false
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path != dir && d.Name() == ".git" {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// CurrentWorktree returns the linked worktree of repoName containing the
+// current directory. ok is false in the main worktree or outside the
+// repository.
+func CurrentWorktree(repoName string) (Worktree, bool) {
+	output, err := run.Output(runner.Command("git", "rev-parse", "--show-toplevel"))
+	if err != nil {
+		return Worktree{}, false
+	}
+	root := canonicalPath(strings.TrimSpace(string(output)))
+
+	worktrees, err := ListWorktrees(repoName)
+	if err != nil {
+		return Worktree{}, false
+	}
+	for _, wt := range worktrees {
+		if canonicalPath(wt.Path) == root {
+			return wt, true
+		}
+	}
+	return Worktree{}, false
+}
+
+// canonicalPath resolves symlinks so paths reported by git and paths built
+// from the base directory compare equal.
+func canonicalPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	// The path may no longer exist (prunable worktrees); resolve its parent
+	if parent, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		return filepath.Join(parent, filepath.Base(path))
+	}
+	return filepath.Clean(path)
+}
+
+func GetHeadCommit(worktreePath string) (string, error) {
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "rev-parse", "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD in %s: %w", worktreePath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DiffHead returns a binary-safe patch of all staged and unstaged changes to
+// tracked files in the worktree.
+func DiffHead(worktreePath string) ([]byte, error) {
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "diff", "HEAD", "--binary"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", worktreePath, err)
+	}
+	return output, nil
+}
+
+// ListUntrackedFiles returns untracked, non-ignored files relative to the
+// worktree root.
+func ListUntrackedFiles(worktreePath string) ([]string, error) {
+	output, err := run.Output(runner.Command("git", "-C", worktreePath, "ls-files", "--others", "--exclude-standard", "-z"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files in %s: %w", worktreePath, err)
+	}
+
+	var files []string
+	for _, f := range strings.Split(string(output), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+func ApplyPatch(worktreePath, patchPath string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "-C", worktreePath, "apply", "--whitespace=nowarn", patchPath))
+	if err != nil {
+		return fmt.Errorf("failed to apply patch %s: %s", patchPath, string(output))
+	}
+	return nil
+}
+
+// LockWorktree prevents the worktree from being pruned, moved or removed.
+func LockWorktree(worktreePath, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, worktreePath)
+	output, err := run.CombinedOutput(runner.Command("git", args...))
+	if err != nil {
+		return fmt.Errorf("failed to lock worktree: %s", string(output))
+	}
+	return nil
+}
+
+func UnlockWorktree(worktreePath string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "worktree", "unlock", worktreePath))
+	if err != nil {
+		return fmt.Errorf("failed to unlock worktree: %s", string(output))
+	}
+	return nil
+}
+
+// MoveWorktree moves the worktree at worktreePath to newPath, keeping its
+// checkout, uncommitted changes and untracked files.
+func MoveWorktree(worktreePath, newPath string) error {
+	output, err := run.CombinedOutput(runner.Command("git", "worktree", "move", worktreePath, newPath))
+	if err != nil {
+		return fmt.Errorf("failed to move worktree: %s", string(output))
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/todoengineering/wt/internal/git"
+)
+
+// Worktree is what wt remembers about a worktree it created. Records live
+// under the worktree base directory rather than inside the worktree so they
+// never show up in git status. Records are keyed by worktree directory, so
+// they also map each directory back to the branch it was created for.
+type Worktree struct {
+	Project string `json:"project"`
+	Name    string `json:"name"`
+	Branch  string `json:"branch,omitempty"`
+	// Base is the ref the branch was created from (e.g. origin/main) and
+	// BaseCommit the commit it pointed at then
+	Base       string    `json:"base,omitempty"`
+	BaseCommit string    `json:"base_commit,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	// SparsePaths are the directories of a sparse checkout, in the order
+	// given; editors and tmux open in the first
+	SparsePaths []string `json:"sparse_paths,omitempty"`
+	// Description and Ticket say what the worktree is for; set with
+	// `wt new --description/--ticket` or `wt describe`
+	Description string `json:"description,omitempty"`
+	Ticket      string `json:"ticket,omitempty"`
+	// Template is the branch_template the branch name was built from
+	Template string `json:"template,omitempty"`
+	// LastOpenedAt and OpenCount order pickers by frecency; pinned
+	// worktrees come first
+	LastOpenedAt time.Time `json:"last_opened_at,omitzero"`
+	OpenCount    int       `json:"open_count,omitempty"`
+	Pinned       bool      `json:"pinned,omitempty"`
+}
+
+// Frecency scores how much a worktree is used: how often it was opened,
+// weighted by how recently, the way shell jump tools rank directories.
+func (w Worktree) Frecency(now time.Time) float64 {
+	if w.OpenCount == 0 || w.LastOpenedAt.IsZero() {
+		return 0
+	}
+	count := float64(w.OpenCount)
+	switch age := now.Sub(w.LastOpenedAt); {
+	case age < time.Hour:
+		return count * 4
+	case age < 24*time.Hour:
+		return count * 2
+	case age < 7*24*time.Hour:
+		return count / 2
+	default:
+		return count / 4
+	}
+}
+
+// GetMetadataDir returns where worktree records are stored.
+func GetMetadataDir() string {
+	return filepath.Join(git.GetWorktreeBaseDir(), ".wt", "worktrees")
+}
+
+// recordPath mirrors the worktree's directory, so nested worktrees such as
+// feature/login get distinct records from flat ones such as feature_login.
+func recordPath(project, name string) string {
+	return filepath.Join(GetMetadataDir(), project, filepath.FromSlash(name)+".json")
+}
+
+// Load returns the record for the named worktree, or nil if wt has none.
+func Load(project, name string) (*Worktree, error) {
+	data, err := os.ReadFile(recordPath(project, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read worktree metadata: %w", err)
+	}
+
+	var record Worktree
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse worktree metadata: %w", err)
+	}
+	return &record, nil
+}
+
+// Save writes record, replacing any previous record for the same worktree.
+func Save(record Worktree) error {
+	path := recordPath(record.Project, record.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode worktree metadata: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write worktree metadata: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the record for the named worktree, if any.
+func Remove(project, name string) error {
+	if err := os.Remove(recordPath(project, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove worktree metadata: %w", err)
+	}
+	git.RemoveEmptyParents(recordPath(project, name), filepath.Join(GetMetadataDir(), project))
+	return nil
+}
+
+// List returns the records of project's worktrees, or of every project's
+// when project is "".
+func List(project string) ([]Worktree, error) {
+	var records []Worktree
+	err := filepath.WalkDir(filepath.Join(GetMetadataDir(), project), func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var record Worktree
+		// Skip unreadable records rather than failing every listing
+		if json.Unmarshal(data, &record) == nil {
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worktree metadata: %w", err)
+	}
+	return records, nil
+}
+
+// Update applies change to the named worktree's record and saves it,
+// creating the record when wt has none, e.g. for worktrees made with plain
+// `git worktree add`.
+func Update(project, name, branch string, change func(*Worktree)) error {
+	record, err := Load(project, name)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		record = &Worktree{Project: project, Name: name, Branch: branch}
+	}
+	change(record)
+	return Save(*record)
+}
+
+// MarkOpened records that the named worktree was opened at.
+func MarkOpened(project, name, branch string, at time.Time) error {
+	return Update(project, name, branch, func(record *Worktree) {
+		record.LastOpenedAt = at
+		record.OpenCount++
+	})
+}
+
+// Rename moves the record of oldName to newName and updates its branch when
+// branch is non-empty. Worktrees without a record are left alone.
+func Rename(project, oldName, newName, branch string) error {
+	record, err := Load(project, oldName)
+	if err != nil || record == nil {
+		return err
+	}
+
+	record.Name = newName
+	if branch != "" {
+		record.Branch = branch
+	}
+	if err := Save(*record); err != nil {
+		return err
+	}
+	return Remove(project, oldName)
+}
+
+// MoveProject refiles the record of the named worktree from oldProject under
+// newProject. Worktrees without a record are left alone.
+func MoveProject(oldProject, newProject, name string) error {
+	record, err := Load(oldProject, name)
+	if err != nil || record == nil {
+		return err
+	}
+
+	record.Project = newProject
+	if err := Save(*record); err != nil {
+		return err
+	}
+	return Remove(oldProject, name)
+}
+
+// GetBase returns the recorded base ref of the named worktree, or "" if none
+// was recorded.
+func GetBase(project, name string) string {
+	record, err := Load(project, name)
+	if err != nil || record == nil {
+		return ""
+	}
+	return record.Base
+}
@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Response is a scripted result for Fake.
+type Response struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// ExitError is returned by Fake for responses with a non-zero exit code.
+type ExitError struct {
+	Code   int
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("exit status %d: %s", e.Code, strings.TrimSpace(e.Stderr))
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+type rule struct {
+	prefix   string
+	response Response
+}
+
+// Fake is a Runner for tests. It records every command it is asked to run
+// and answers with the most recently scripted response whose command line is
+// a prefix of the command's, word by word. Commands nobody scripted fail
+// with exit code 1, so unexpected calls show up as errors.
+type Fake struct {
+	mu    sync.Mutex
+	rules []rule
+	calls []Cmd
+}
+
+// NewFake returns a Fake with nothing scripted.
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// On scripts the response for commands starting with command, e.g.
+// "git rev-parse --git-common-dir". Later calls take precedence, so tests
+// can override a general rule with a more specific or newer one.
+func (f *Fake) On(command string, response Response) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append(f.rules, rule{prefix: command, response: response})
+	return f
+}
+
+// OnOutput scripts a successful command printing stdout.
+func (f *Fake) OnOutput(command, stdout string) *Fake {
+	return f.On(command, Response{Stdout: stdout})
+}
+
+// OnFail scripts a command failing with exit code 1 and stderr.
+func (f *Fake) OnFail(command, stderr string) *Fake {
+	return f.On(command, Response{Stderr: stderr, ExitCode: 1})
+}
+
+// Calls returns the command lines run so far, in order.
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]string, len(f.calls))
+	for i, c := range f.calls {
+		calls[i] = c.String()
+	}
+	return calls
+}
+
+// Commands returns the commands run so far, including their environment.
+func (f *Fake) Commands() []Cmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Cmd(nil), f.calls...)
+}
+
+// Ran reports whether a command starting with command was run.
+func (f *Fake) Ran(command string) bool {
+	for _, call := range f.Calls() {
+		if hasWordPrefix(call, command) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Fake) respond(c Cmd) (Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, c)
+
+	line := c.String()
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if hasWordPrefix(line, f.rules[i].prefix) {
+			r := f.rules[i].response
+			if r.ExitCode != 0 {
+				return r, &ExitError{Code: r.ExitCode, Stderr: r.Stderr}
+			}
+			return r, nil
+		}
+	}
+
+	stderr := fmt.Sprintf("fake runner: unexpected command: %s", line)
+	return Response{Stderr: stderr, ExitCode: 1}, &ExitError{Code: 1, Stderr: stderr}
+}
+
+func hasWordPrefix(line, prefix string) bool {
+	return line == prefix || strings.HasPrefix(line, prefix+" ")
+}
+
+func (f *Fake) Output(c Cmd) ([]byte, error) {
+	r, err := f.respond(c)
+	return []byte(r.Stdout), err
+}
+
+func (f *Fake) CombinedOutput(c Cmd) ([]byte, error) {
+	r, err := f.respond(c)
+	return []byte(r.Stdout + r.Stderr), err
+}
+
+func (f *Fake) Run(c Cmd) error {
+	r, err := f.respond(c)
+	if c.Stdout != nil {
+		fmt.Fprint(c.Stdout, r.Stdout)
+	}
+	if c.Stderr != nil {
+		fmt.Fprint(c.Stderr, r.Stderr)
+	}
+	return err
+}
+
+func (f *Fake) Start(c Cmd) error {
+	_, err := f.respond(c)
+	return err
+}
@@ -0,0 +1,55 @@
+package runner
+
+import "testing"
+
+func TestFakeMatchesWordPrefixesNewestFirst(t *testing.T) {
+	fake := NewFake()
+	fake.OnOutput("git rev-parse", "general")
+	fake.OnOutput("git rev-parse --show-toplevel", "specific")
+
+	out, err := fake.Output(Command("git", "rev-parse", "--show-toplevel"))
+	if err != nil || string(out) != "specific" {
+		t.Errorf("Output() = %q, %v; want specific", out, err)
+	}
+
+	out, _ = fake.Output(Command("git", "rev-parse", "--git-dir"))
+	if string(out) != "general" {
+		t.Errorf("Output() = %q, want general", out)
+	}
+
+	// "git rev" is not a word prefix of "git rev-parse"
+	fake.OnOutput("git rev", "wrong")
+	out, _ = fake.Output(Command("git", "rev-parse", "HEAD"))
+	if string(out) != "general" {
+		t.Errorf("Output() = %q, want general", out)
+	}
+}
+
+func TestFakeFailures(t *testing.T) {
+	fake := NewFake()
+	fake.OnFail("git merge-base --is-ancestor", "")
+
+	err := fake.Run(Command("git", "merge-base", "--is-ancestor", "a", "b"))
+	if ExitCode(err) != 1 {
+		t.Errorf("ExitCode() = %d, want 1", ExitCode(err))
+	}
+
+	if _, err := fake.Output(Command("git", "push")); err == nil {
+		t.Error("unscripted commands should fail")
+	}
+
+	want := []string{"git merge-base --is-ancestor a b", "git push"}
+	if got := fake.Calls(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Calls() = %q, want %q", got, want)
+	}
+}
+
+func TestExecExitCode(t *testing.T) {
+	err := Exec{}.Run(Command("sh", "-c", "exit 3"))
+	if ExitCode(err) != 3 {
+		t.Errorf("ExitCode() = %d, want 3", ExitCode(err))
+	}
+	if ExitCode(nil) != -1 {
+		t.Errorf("ExitCode(nil) = %d, want -1", ExitCode(nil))
+	}
+}
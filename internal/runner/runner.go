@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrTimeout is returned when a command runs longer than its Timeout.
+var ErrTimeout = errors.New("command timed out")
+
+// Cmd is an external command to run.
+type Cmd struct {
+	Name string
+	Args []string
+	// Env holds variables added to the current environment
+	Env []string
+	// Stdin, Stdout and Stderr are only used by Run and Start; nil means
+	// no input and discarded output
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Timeout kills the command after this long; zero waits indefinitely
+	Timeout time.Duration
+}
+
+// Command returns a Cmd running name with args.
+func Command(name string, args ...string) Cmd {
+	return Cmd{Name: name, Args: args}
+}
+
+// String returns the command line, e.g. "git worktree list --porcelain".
+func (c Cmd) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Runner runs external commands. Exec runs them for real; Fake records them
+// and answers with scripted results in tests.
+type Runner interface {
+	// Output runs c and returns its standard output.
+	Output(c Cmd) ([]byte, error)
+	// CombinedOutput runs c and returns its standard output and error.
+	CombinedOutput(c Cmd) ([]byte, error)
+	// Run runs c attached to its Stdin, Stdout and Stderr and waits for it.
+	Run(c Cmd) error
+	// Start starts c attached to its Stdin, Stdout and Stderr without waiting.
+	Start(c Cmd) error
+}
+
+// ExitCode returns the exit code of a command that ran and failed, or -1 if
+// err isn't such a failure.
+func ExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	var fakeErr *ExitError
+	if errors.As(err, &fakeErr) {
+		return fakeErr.Code
+	}
+	return -1
+}
+
+// Exec runs commands with os/exec.
+type Exec struct{}
+
+func (Exec) Output(c Cmd) ([]byte, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	output, err := c.command(ctx).Output()
+	return output, c.wrap(ctx, err)
+}
+
+func (Exec) CombinedOutput(c Cmd) ([]byte, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	output, err := c.command(ctx).CombinedOutput()
+	return output, c.wrap(ctx, err)
+}
+
+func (Exec) Run(c Cmd) error {
+	ctx, cancel := c.context()
+	defer cancel()
+	cmd := c.command(ctx)
+	c.attach(cmd)
+	return c.wrap(ctx, cmd.Run())
+}
+
+func (Exec) Start(c Cmd) error {
+	// The command outlives this call, so it can't be bound to a timeout
+	cmd := c.command(context.Background())
+	c.attach(cmd)
+	return cmd.Start()
+}
+
+func (c Cmd) context() (context.Context, context.CancelFunc) {
+	if c.Timeout > 0 {
+		return context.WithTimeout(context.Background(), c.Timeout)
+	}
+	return context.Background(), func() {}
+}
+
+func (c Cmd) command(ctx context.Context) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	// Children (ssh, credential helpers) may keep output pipes open after
+	// the command is killed; don't wait on them forever
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+func (c Cmd) attach(cmd *exec.Cmd) {
+	cmd.Stdin = c.Stdin
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+}
+
+func (c Cmd) wrap(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrTimeout, c.Timeout)
+	}
+	return err
+}
@@ -3,13 +3,24 @@ package tmux
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+
+	"github.com/todoengineering/wt/internal/runner"
 )
 
+// run executes every tmux command; tests replace it with a runner.Fake.
+var run runner.Runner = runner.Exec{}
+
+// SetRunner replaces the runner used for tmux commands and returns the
+// previous one.
+func SetRunner(r runner.Runner) runner.Runner {
+	prev := run
+	run = r
+	return prev
+}
+
 func IsInstalled() bool {
-	cmd := exec.Command("which", "tmux")
-	err := cmd.Run()
+	err := run.Run(runner.Command("which", "tmux"))
 	return err == nil
 }
 
@@ -18,8 +29,7 @@ func IsInsideTmux() bool {
 }
 
 func SessionExists(sessionName string) bool {
-	cmd := exec.Command("tmux", "has-session", "-t", sessionName)
-	err := cmd.Run()
+	err := run.Run(runner.Command("tmux", "has-session", "-t", sessionName))
 	return err == nil
 }
 
@@ -35,8 +45,7 @@ func CreateSession(sessionName, workingDir string) error {
 	}
 
 	// Create new detached session
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", workingDir)
-	output, err := cmd.CombinedOutput()
+	output, err := run.CombinedOutput(runner.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", workingDir))
 	if err != nil {
 		return fmt.Errorf("failed to create tmux session: %s", string(output))
 	}
@@ -57,22 +66,20 @@ func CreateSessionWithCommand(sessionName, workingDir, command string) error {
 	}
 
 	// Create new session with the editor command
-	var cmd *exec.Cmd
 	if IsInsideTmux() {
 		// Create detached session and then switch
-		cmd = exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", workingDir, command)
-		output, err := cmd.CombinedOutput()
+		output, err := run.CombinedOutput(runner.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", workingDir, command))
 		if err != nil {
 			return fmt.Errorf("failed to create tmux session: %s", string(output))
 		}
 		return SwitchToSession(sessionName)
 	} else {
 		// Create and attach to session directly with the command
-		cmd = exec.Command("tmux", "new-session", "-s", sessionName, "-c", workingDir, command)
+		cmd := runner.Command("tmux", "new-session", "-s", sessionName, "-c", workingDir, command)
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
-		return cmd.Start()
+		return run.Start(cmd)
 	}
 }
 
@@ -82,8 +89,7 @@ func SendCommandToSession(sessionName, command string) error {
 	}
 
 	// Send command to the first pane of the session
-	cmd := exec.Command("tmux", "send-keys", "-t", sessionName+":0.0", command, "Enter")
-	output, err := cmd.CombinedOutput()
+	output, err := run.CombinedOutput(runner.Command("tmux", "send-keys", "-t", sessionName+":0.0", command, "Enter"))
 	if err != nil {
 		return fmt.Errorf("failed to send command to tmux session: %s", string(output))
 	}
@@ -96,25 +102,21 @@ func SwitchToSession(sessionName string) error {
 		return nil
 	}
 
-	var cmd *exec.Cmd
-	if IsInsideTmux() {
-		// If inside tmux, switch client
-		cmd = exec.Command("tmux", "switch-client", "-t", sessionName)
-	} else {
-		// If outside tmux, attach to session
-		cmd = exec.Command("tmux", "attach-session", "-t", sessionName)
+	if !IsInsideTmux() {
+		// If outside tmux, attach to session. Attaching blocks until the
+		// session is detached, so we don't wait for it
+		cmd := runner.Command("tmux", "attach-session", "-t", sessionName)
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
+		run.Start(cmd)
+		return nil
 	}
 
-	output, err := cmd.CombinedOutput()
+	// If inside tmux, switch client
+	output, err := run.CombinedOutput(runner.Command("tmux", "switch-client", "-t", sessionName))
 	if err != nil {
-		// Only return error if we're trying to switch from inside tmux
-		// Attaching from outside tmux will block, so we don't wait for it
-		if IsInsideTmux() {
-			return fmt.Errorf("failed to switch to tmux session: %s", string(output))
-		}
+		return fmt.Errorf("failed to switch to tmux session: %s", string(output))
 	}
 
 	return nil
@@ -130,8 +132,7 @@ func KillSession(sessionName string) error {
 		return nil // Session doesn't exist, nothing to do
 	}
 
-	cmd := exec.Command("tmux", "kill-session", "-t", sessionName)
-	output, err := cmd.CombinedOutput()
+	output, err := run.CombinedOutput(runner.Command("tmux", "kill-session", "-t", sessionName))
 	if err != nil {
 		return fmt.Errorf("failed to kill tmux session: %s", string(output))
 	}
@@ -139,6 +140,19 @@ func KillSession(sessionName string) error {
 	return nil
 }
 
+func RenameSession(oldName, newName string) error {
+	if !IsInstalled() {
+		return nil
+	}
+
+	output, err := run.CombinedOutput(runner.Command("tmux", "rename-session", "-t", oldName, newName))
+	if err != nil {
+		return fmt.Errorf("failed to rename tmux session: %s", string(output))
+	}
+
+	return nil
+}
+
 type TmuxWindow struct {
 	Name    string
 	Command string
@@ -162,35 +176,31 @@ func CreateSessionWithNamedWindows(sessionName, workingDir string, windows []Tmu
 
 	// Create new detached session with first window
 	firstWindow := windows[0]
-	var cmd *exec.Cmd
+	cmd := runner.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", workingDir, "-n", firstWindow.Name)
 	if firstWindow.Command != "" {
-		cmd = exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", workingDir, "-n", firstWindow.Name, firstWindow.Command)
-	} else {
-		cmd = exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", workingDir, "-n", firstWindow.Name)
+		cmd.Args = append(cmd.Args, firstWindow.Command)
 	}
 
-	output, err := cmd.CombinedOutput()
+	output, err := run.CombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to create tmux session: %s", string(output))
 	}
 
 	// Create additional windows
 	for _, window := range windows[1:] {
+		cmd := runner.Command("tmux", "new-window", "-t", sessionName, "-n", window.Name, "-c", workingDir)
 		if window.Command != "" {
-			cmd = exec.Command("tmux", "new-window", "-t", sessionName, "-n", window.Name, "-c", workingDir, window.Command)
-		} else {
-			cmd = exec.Command("tmux", "new-window", "-t", sessionName, "-n", window.Name, "-c", workingDir)
+			cmd.Args = append(cmd.Args, window.Command)
 		}
 
-		output, err := cmd.CombinedOutput()
+		output, err := run.CombinedOutput(cmd)
 		if err != nil {
 			return fmt.Errorf("failed to create tmux window '%s': %s", window.Name, string(output))
 		}
 	}
 
 	// Select the first window (tmux default behavior)
-	cmd = exec.Command("tmux", "select-window", "-t", sessionName+":0")
-	cmd.Run() // Ignore errors for this command
+	run.Run(runner.Command("tmux", "select-window", "-t", sessionName+":0")) // Ignore errors for this command
 
 	// Switch to the new session
 	return SwitchToSession(sessionName)
@@ -208,3 +218,23 @@ func SanitizeSessionName(name string) string {
 	)
 	return replacer.Replace(name)
 }
+
+func ListSessions() []string {
+	if !IsInstalled() {
+		return nil
+	}
+
+	output, err := run.Output(runner.Command("tmux", "list-sessions", "-F", "#{session_name}"))
+	if err != nil {
+		// No server running means there are no sessions
+		return nil
+	}
+
+	var sessions []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			sessions = append(sessions, name)
+		}
+	}
+	return sessions
+}
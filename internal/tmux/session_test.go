@@ -0,0 +1,115 @@
+package tmux
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/todoengineering/wt/internal/runner"
+)
+
+func useFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	fake := runner.NewFake()
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+	return fake
+}
+
+func TestCreateSessionWithNamedWindows(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	fake := useFake(t)
+	fake.OnOutput("which tmux", "/usr/bin/tmux\n")
+	fake.OnFail("tmux has-session", "can't find session")
+	fake.OnOutput("tmux new-session", "")
+	fake.OnOutput("tmux new-window", "")
+	fake.OnOutput("tmux select-window", "")
+	fake.OnOutput("tmux switch-client", "")
+
+	windows := []TmuxWindow{
+		{Name: "editor", Command: "nvim ."},
+		{Name: "server", Command: "npm run dev"},
+		{Name: "shell"},
+	}
+	if err := CreateSessionWithNamedWindows("app-feature", "/wt/app/feature", windows); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"which tmux",
+		"tmux has-session -t app-feature",
+		"tmux new-session -d -s app-feature -c /wt/app/feature -n editor nvim .",
+		"tmux new-window -t app-feature -n server -c /wt/app/feature npm run dev",
+		"tmux new-window -t app-feature -n shell -c /wt/app/feature",
+		"tmux select-window -t app-feature:0",
+		"which tmux",
+		"tmux switch-client -t app-feature",
+	}
+	if got := fake.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCreateSessionSwitchesToExistingSession(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	fake := useFake(t)
+	fake.OnOutput("which tmux", "/usr/bin/tmux\n")
+	fake.OnOutput("tmux has-session", "")
+	fake.OnOutput("tmux switch-client", "")
+
+	if err := CreateSession("app-feature", "/wt/app/feature"); err != nil {
+		t.Fatal(err)
+	}
+	if fake.Ran("tmux new-session") {
+		t.Errorf("created a session although one exists: %q", fake.Calls())
+	}
+	if !fake.Ran("tmux switch-client -t app-feature") {
+		t.Errorf("did not switch to the existing session: %q", fake.Calls())
+	}
+}
+
+func TestCreateSessionWithoutTmuxInstalled(t *testing.T) {
+	fake := useFake(t)
+	fake.OnFail("which tmux", "")
+
+	if err := CreateSession("app-feature", "/wt/app/feature"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.Calls(); !reflect.DeepEqual(got, []string{"which tmux"}) {
+		t.Errorf("calls = %q", got)
+	}
+}
+
+func TestSwitchToSessionReportsFailureInsideTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	fake := useFake(t)
+	fake.OnOutput("which tmux", "/usr/bin/tmux\n")
+	fake.OnFail("tmux switch-client", "can't find session: nope")
+
+	if err := SwitchToSession("nope"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestSanitizeSessionName(t *testing.T) {
+	tests := map[string]string{
+		"app-feature":       "app-feature",
+		"app-feature/login": "app-feature_login",
+		"my.app-v1.2":       "my_app-v1_2",
+		"app-a b:c":         "app-a_b_c",
+	}
+	for in, want := range tests {
+		if got := SanitizeSessionName(in); got != want {
+			t.Errorf("SanitizeSessionName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	fake := useFake(t)
+	fake.OnOutput("which tmux", "/usr/bin/tmux\n")
+	fake.OnOutput("tmux list-sessions", "app-main\napp-feature\n\n")
+
+	if got := ListSessions(); !reflect.DeepEqual(got, []string{"app-main", "app-feature"}) {
+		t.Errorf("ListSessions() = %q", got)
+	}
+}
@@ -0,0 +1,384 @@
+package trash
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/todoengineering/wt/internal/config"
+	"github.com/todoengineering/wt/internal/git"
+)
+
+const (
+	metaFile  = "meta.json"
+	patchFile = "changes.patch"
+	filesDir  = "files"
+	// Commits captured in the trash are kept alive under this ref namespace
+	refPrefix = "refs/wt-trash/"
+)
+
+// Entry is a deleted worktree captured in the trash.
+type Entry struct {
+	ID        string    `json:"id"`
+	Project   string    `json:"project"`
+	Name      string    `json:"name"`
+	Branch    string    `json:"branch"`
+	Head      string    `json:"head"`
+	Path      string    `json:"path"`
+	GitDir    string    `json:"git_dir"`
+	DeletedAt time.Time `json:"deleted_at"`
+	HasPatch  bool      `json:"has_patch"`
+	Files     []string  `json:"files,omitempty"`
+
+	// Dir is where the entry is stored on disk
+	Dir string `json:"-"`
+}
+
+func (e Entry) ref() string {
+	return refPrefix + e.ID
+}
+
+// Detached reports whether the worktree wasn't on a branch when deleted.
+func (e Entry) Detached() bool {
+	return e.Branch == "" || e.Branch == "unknown" || strings.HasPrefix(e.Branch, "detached")
+}
+
+// Enabled reports whether deleted worktrees should be captured at all.
+func Enabled() bool {
+	return config.GetTrashRetentionDays() > 0
+}
+
+// GetTrashDir returns the trash location under the worktree base directory.
+func GetTrashDir() string {
+	return filepath.Join(git.GetWorktreeBaseDir(), ".trash")
+}
+
+// Capture saves the worktree's branch tip, uncommitted changes, untracked
+// files and configured copy_files so it can be restored after deletion.
+func Capture(project string, worktree git.Worktree) (*Entry, error) {
+	gitDir, err := git.GetGitCommonDir()
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := git.GetHeadCommit(worktree.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedAt := time.Now()
+	entry := &Entry{
+		ID:        fmt.Sprintf("%s-%s", git.SanitizeBranchName(worktree.Name), deletedAt.Format("20060102-150405")),
+		Project:   project,
+		Name:      worktree.Name,
+		Branch:    worktree.Branch,
+		Head:      head,
+		Path:      worktree.Path,
+		GitDir:    gitDir,
+		DeletedAt: deletedAt,
+	}
+	entry.Dir = filepath.Join(GetTrashDir(), project, entry.ID)
+
+	if err := os.MkdirAll(entry.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	if err := capture(entry, worktree.Path); err != nil {
+		os.RemoveAll(entry.Dir)
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func capture(entry *Entry, worktreePath string) error {
+	patch, err := git.DiffHead(worktreePath)
+	if err != nil {
+		return err
+	}
+	if len(patch) > 0 {
+		if err := os.WriteFile(filepath.Join(entry.Dir, patchFile), patch, 0644); err != nil {
+			return fmt.Errorf("failed to save changes: %w", err)
+		}
+		entry.HasPatch = true
+	}
+
+	files, err := filesToCapture(worktreePath)
+	if err != nil {
+		return err
+	}
+	for _, rel := range files {
+		if err := copyFile(filepath.Join(worktreePath, rel), filepath.Join(entry.Dir, filesDir, rel)); err != nil {
+			return fmt.Errorf("failed to save %s: %w", rel, err)
+		}
+	}
+	entry.Files = files
+
+	if err := git.UpdateRef(entry.GitDir, entry.ref(), entry.Head); err != nil {
+		return err
+	}
+
+	if err := writeMeta(entry); err != nil {
+		git.DeleteRef(entry.GitDir, entry.ref())
+		return err
+	}
+
+	return nil
+}
+
+// filesToCapture returns untracked files plus anything matching copy_files,
+// which is usually ignored by git (.env, certificates, ...).
+func filesToCapture(worktreePath string) ([]string, error) {
+	untracked, err := git.ListUntrackedFiles(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	add := func(rel string) {
+		if !seen[rel] {
+			seen[rel] = true
+			files = append(files, rel)
+		}
+	}
+
+	for _, rel := range untracked {
+		add(rel)
+	}
+
+	for _, pattern := range config.GetCopyFiles() {
+		matches, err := filepath.Glob(filepath.Join(worktreePath, pattern))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || info.IsDir() {
+				continue
+			}
+			if rel, err := filepath.Rel(worktreePath, match); err == nil {
+				add(rel)
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func writeMeta(entry *Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(entry.Dir, metaFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write trash metadata: %w", err)
+	}
+	return nil
+}
+
+// List returns trash entries for project, or for every project when project
+// is empty, newest first.
+func List(project string) ([]Entry, error) {
+	trashDir := GetTrashDir()
+
+	var projectDirs []string
+	if project != "" {
+		projectDirs = []string{filepath.Join(trashDir, project)}
+	} else {
+		// Project names such as github.com/acme/api span several directories;
+		// a project directory is one holding entries
+		seen := make(map[string]bool)
+		err := filepath.WalkDir(trashDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if _, err := os.Stat(filepath.Join(path, metaFile)); err == nil {
+				if dir := filepath.Dir(path); !seen[dir] {
+					seen[dir] = true
+					projectDirs = append(projectDirs, dir)
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trash directory: %w", err)
+		}
+	}
+
+	var result []Entry
+	for _, projectDir := range projectDirs {
+		entries, err := os.ReadDir(projectDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read trash directory: %w", err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			dir := filepath.Join(projectDir, e.Name())
+			data, err := os.ReadFile(filepath.Join(dir, metaFile))
+			if err != nil {
+				// Skip incomplete entries
+				continue
+			}
+			var entry Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				continue
+			}
+			entry.Dir = dir
+			result = append(result, entry)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DeletedAt.After(result[j].DeletedAt)
+	})
+
+	return result, nil
+}
+
+// Find returns the newest entry of project whose ID or worktree name matches.
+func Find(project, name string) (*Entry, error) {
+	entries, err := List(project)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.ID == name || e.Name == name {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("no trashed worktree '%s' found for '%s'", name, project)
+}
+
+// Restore recreates the worktree from entry in the current repository and
+// removes the entry from the trash. It returns the new worktree path.
+func Restore(entry Entry) (string, error) {
+	var worktreePath string
+	var err error
+
+	if entry.Detached() {
+		worktreePath, err = git.CreateDetachedWorktree(entry.Project, entry.Name, entry.Head)
+	} else {
+		if exists, path := git.GitWorktreeExistsForBranch(entry.Branch); exists {
+			return "", fmt.Errorf("branch '%s' is already checked out at %s", entry.Branch, path)
+		}
+		if !git.LocalBranchExists(entry.Branch) {
+			// The branch was deleted along with the worktree; recreate it at the saved tip
+			if err := git.CreateBranchAt(entry.Branch, entry.Head); err != nil {
+				return "", err
+			}
+		} else if head, err := git.ResolveCommit("refs/heads/" + entry.Branch); err == nil && head != entry.Head {
+			fmt.Fprintf(os.Stderr, "Warning: branch '%s' moved since deletion; saved tip was %s\n", entry.Branch, entry.Head)
+		}
+		worktreePath, err = git.CreateWorktree(entry.Project, entry.Name, entry.Branch)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var warnings []string
+	if entry.HasPatch {
+		if err := git.ApplyPatch(worktreePath, filepath.Join(entry.Dir, patchFile)); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	for _, rel := range entry.Files {
+		if err := copyFile(filepath.Join(entry.Dir, filesDir, rel), filepath.Join(worktreePath, rel)); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", rel, err))
+		}
+	}
+
+	if len(warnings) > 0 {
+		// Keep the entry so nothing is lost; the user can inspect it by hand
+		return worktreePath, fmt.Errorf("restored with problems (trash entry kept at %s):\n  %s", entry.Dir, strings.Join(warnings, "\n  "))
+	}
+
+	return worktreePath, Remove(entry)
+}
+
+// Remove permanently deletes entry from the trash.
+func Remove(entry Entry) error {
+	if err := git.DeleteRef(entry.GitDir, entry.ref()); err != nil {
+		// The repository may be gone; the saved files are still worth removing
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if err := os.RemoveAll(entry.Dir); err != nil {
+		return fmt.Errorf("failed to remove trash entry: %w", err)
+	}
+	return nil
+}
+
+// Expire permanently deletes entries older than the configured retention and
+// returns the removed entries.
+func Expire() ([]Entry, error) {
+	days := config.GetTrashRetentionDays()
+	if days <= 0 {
+		return nil, nil
+	}
+
+	entries, err := List("")
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	var expired []Entry
+	for _, e := range entries {
+		if e.DeletedAt.Before(cutoff) {
+			if err := Remove(e); err != nil {
+				return expired, err
+			}
+			expired = append(expired, e)
+		}
+	}
+	return expired, nil
+}
+
+// ExpiresAt returns when entry will be removed automatically.
+func (e Entry) ExpiresAt() time.Time {
+	return e.DeletedAt.AddDate(0, 0, config.GetTrashRetentionDays())
+}
+
+func copyFile(src, dst string) error {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	sourceInfo, err := sourceFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	destFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, sourceInfo.Mode())
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	_, err = io.Copy(destFile, sourceFile)
+	return err
+}
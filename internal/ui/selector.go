@@ -77,6 +77,12 @@ func (m model) View() string {
 // Select displays a list of items and allows the user to select one.
 // It returns the selected Item and any error that occurred.
 func Select(items []Item, title string) (Item, error) {
+	return SelectFiltered(items, title, "")
+}
+
+// SelectFiltered is Select with the list already filtered on filter, which
+// the user can edit or clear to see every item.
+func SelectFiltered(items []Item, title, filter string) (Item, error) {
 	listItems := make([]list.Item, len(items))
 	for i, item := range items {
 		listItems[i] = item
@@ -92,6 +98,9 @@ func Select(items []Item, title string) (Item, error) {
 	l.Styles.Title = titleStyle
 	l.Styles.PaginationStyle = paginationStyle
 	l.Styles.HelpStyle = helpStyle
+	if filter != "" {
+		l.SetFilterText(filter)
+	}
 
 	m := model{list: l}
 